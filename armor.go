@@ -0,0 +1,119 @@
+package shamir
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const ArmorType string = "SHAMIR SHARE"
+
+var ErrInvalidArmor error = errors.New("armored share is malformed or missing required headers")
+
+var armorRegexp = regexp.MustCompile(`(?s)-----BEGIN SHAMIR SHARE-----.*?-----END SHAMIR SHARE-----`)
+
+// Armor encodes the share as a PEM block with readable headers, so it can be pasted into emails or tickets
+// holder and created are informational, and are omitted when empty
+func (share Share) Armor(holder string, created time.Time) []byte {
+	headers := map[string]string{
+		"Secret-ID":   share.secret_id,
+		"Polynomial":  share.fieldName(),
+		"Share-Index": share.GetXString(),
+	}
+
+	if share.threshold > 0 {
+		headers["Threshold"] = strconv.Itoa(share.threshold)
+	}
+
+	if share.scheme != SchemeShamir {
+		headers["Scheme"] = string(share.scheme)
+	}
+
+	if holder != "" {
+		headers["Holder"] = strings.Join(strings.Fields(holder), " ")
+	}
+
+	if !created.IsZero() {
+		headers["Created"] = created.UTC().Format(time.RFC3339)
+	}
+
+	if share.IsSigned() {
+		headers["Dealer"] = share.dealer
+		headers["Signature"] = base64.RawStdEncoding.EncodeToString(share.signature)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: ArmorType, Headers: headers, Bytes: share.yBytes()})
+}
+
+// parse the armored shares in input
+// the remaining input is returned with the armored shares removed
+func parseArmoredShares(input string) ([]Share, string, error) {
+	shares := make([]Share, 0)
+
+	for _, armored := range armorRegexp.FindAllString(input, -1) {
+		block, _ := pem.Decode([]byte(armored))
+		if block == nil || block.Type != ArmorType {
+			return nil, "", ErrInvalidArmor
+		}
+
+		share, err := newShareFromArmor(block)
+		if err != nil {
+			return nil, "", err
+		}
+
+		shares = append(shares, share)
+	}
+
+	return shares, armorRegexp.ReplaceAllString(input, ""), nil
+}
+
+func newShareFromArmor(block *pem.Block) (Share, error) {
+	secret_id, ok := block.Headers["Secret-ID"]
+	if !ok || secret_id == "" {
+		return Share{}, ErrInvalidArmor
+	}
+
+	primitivePoly, group, err := parseFieldName(block.Headers["Polynomial"])
+	if err != nil {
+		return Share{}, ErrInvalidArmor
+	}
+
+	xdata, err := strconv.ParseInt(block.Headers["Share-Index"], 10, 64)
+	if err != nil {
+		return Share{}, ErrInvalidArmor
+	}
+
+	y := make([]GfElement, len(block.Bytes))
+	for i := range block.Bytes {
+		y[i] = GfElement(block.Bytes[i])
+	}
+
+	share := NewShare(secret_id, primitivePoly, GfElement(xdata), y)
+	share.group = group
+
+	if threshold, ok := block.Headers["Threshold"]; ok {
+		share.threshold, err = strconv.Atoi(threshold)
+		if err != nil || share.threshold < 1 {
+			return Share{}, ErrInvalidArmor
+		}
+	}
+
+	if scheme, ok := block.Headers["Scheme"]; ok {
+		share.scheme, err = ParseScheme(scheme)
+		if err != nil || share.threshold < 1 {
+			return Share{}, ErrInvalidArmor
+		}
+	}
+
+	if signature, ok := block.Headers["Signature"]; ok {
+		if err := share.parseSignature(block.Headers["Threshold"], block.Headers["Dealer"], signature); err != nil {
+			return Share{}, err
+		}
+	}
+
+	return share, nil
+}
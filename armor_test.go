@@ -0,0 +1,80 @@
+package shamir
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestArmor(t *testing.T) {
+	secret := []byte("This is an armored secret.")
+
+	shamir, err := NewShamirSecret(0x11d, 5, 3, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	armored := string(shamir.shares[1].Armor("Alice Example", created))
+
+	if !strings.HasPrefix(armored, "-----BEGIN SHAMIR SHARE-----\n") {
+		t.Fatalf("missing armor header:\n%s", armored)
+	}
+
+	for _, header := range []string{
+		"Secret-ID: " + shamir.GetId(),
+		"Threshold: 3",
+		"Share-Index: 2",
+		"Holder: Alice Example",
+		"Created: 2024-06-01T12:00:00Z",
+	} {
+		if !strings.Contains(armored, header) {
+			t.Errorf("missing header %q:\n%s", header, armored)
+		}
+	}
+
+	// mix armored and plain shares, surrounded by other text as if pasted into an email
+	input := "Here is my share:\n" + armored + "\nand here is another\n" + shamir.shares[3].String() + "\n" + string(shamir.shares[4].Armor("", time.Time{}))
+
+	shares, err := NewSharesFromString(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(shares) != 3 {
+		t.Fatalf("have %d shares, want 3", len(shares))
+	}
+
+	if shares[0].String() != shamir.shares[1].String() {
+		t.Errorf("have %s, want %s", shares[0], shamir.shares[1])
+	}
+
+	if shares[0].GetThreshold() != 3 {
+		t.Errorf("have threshold %d, want 3", shares[0].GetThreshold())
+	}
+
+	recovered_secret, err := RecoverSecret(shares)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(secret, recovered_secret) {
+		t.Fatalf("have %v, want %v", recovered_secret, secret)
+	}
+}
+
+func TestArmorErrors(t *testing.T) {
+	input := "-----BEGIN SHAMIR SHARE-----\nSecret-ID: 7SPFLJYT\nShare-Index: 3\n\nxYSJU5oTyQcNZHs9SvY=\n-----END SHAMIR SHARE-----\n"
+
+	if _, err := NewSharesFromString(input); err == nil {
+		t.Error("should have rejected a share without a polynomial")
+	}
+
+	for _, threshold := range []string{"0", "-2", "three"} {
+		input := "-----BEGIN SHAMIR SHARE-----\nSecret-ID: 7SPFLJYT\nPolynomial: 11d\nShare-Index: 3\nThreshold: " + threshold + "\n\nxYSJU5oTyQcNZHs9SvY=\n-----END SHAMIR SHARE-----\n"
+		if _, err := NewSharesFromString(input); err != ErrInvalidArmor {
+			t.Errorf("threshold %s: have %v, want %v", threshold, err, ErrInvalidArmor)
+		}
+	}
+}
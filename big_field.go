@@ -0,0 +1,124 @@
+package shamir
+
+import (
+	crand "crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+var ErrNotInvertible error = errors.New("element has no inverse")
+
+// GF(2^m) for m too large for log tables, as used by ssss
+// elements are polynomials over GF(2) stored as the bits of a big integer
+type Gf2mBig struct {
+	m             int
+	primitivePoly *big.Int
+}
+
+func (field Gf2mBig) String() string {
+	return fmt.Sprintf("GF(2^%d) using irreducible polynomial 0x%x", field.m, field.primitivePoly)
+}
+
+func (field Gf2mBig) GetDegree() int {
+	return field.m
+}
+
+func NewBigField(primitivePoly *big.Int) Gf2mBig {
+	return Gf2mBig{
+		m:             primitivePoly.BitLen() - 1,
+		primitivePoly: new(big.Int).Set(primitivePoly),
+	}
+}
+
+// add two elements in the field
+func (field Gf2mBig) Add(a, b *big.Int) *big.Int {
+	return new(big.Int).Xor(a, b)
+}
+
+// subtract two elements in the field
+// addition and subtraction are the same in GF(2^m)
+func (field Gf2mBig) Subtract(a, b *big.Int) *big.Int {
+	return new(big.Int).Xor(a, b)
+}
+
+// multiply two elements in the field using shift-and-add
+func (field Gf2mBig) Multiply(a, b *big.Int) *big.Int {
+	c := new(big.Int)
+	shifted := new(big.Int).Set(a)
+
+	for i := 0; i < field.m; i++ {
+		if b.Bit(i) == 1 {
+			c.Xor(c, shifted)
+		}
+
+		shifted.Lsh(shifted, 1)
+		if shifted.Bit(field.m) == 1 {
+			shifted.Xor(shifted, field.primitivePoly)
+		}
+	}
+
+	return c
+}
+
+// compute the multiplicative inverse of a using the extended Euclidean algorithm
+func (field Gf2mBig) Inverse(a *big.Int) (*big.Int, error) {
+	if a.Sign() == 0 {
+		return nil, ErrNotInvertible
+	}
+
+	u := new(big.Int).Set(a)
+	v := new(big.Int).Set(field.primitivePoly)
+	g1 := big.NewInt(1)
+	g2 := big.NewInt(0)
+	shifted := new(big.Int)
+
+	for u.Cmp(big.NewInt(1)) != 0 {
+		j := u.BitLen() - v.BitLen()
+		if j < 0 {
+			u, v = v, u
+			g1, g2 = g2, g1
+			j = -j
+		}
+
+		u.Xor(u, shifted.Lsh(v, uint(j)))
+		g1.Xor(g1, shifted.Lsh(g2, uint(j)))
+
+		if u.Sign() == 0 {
+			// only possible if the polynomial is reducible
+			return nil, ErrNotInvertible
+		}
+	}
+
+	return g1, nil
+}
+
+// divide a by b
+func (field Gf2mBig) Divide(a, b *big.Int) (*big.Int, error) {
+	if b.Sign() == 0 {
+		return nil, errors.New("division by zero")
+	}
+
+	inverse, err := field.Inverse(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return field.Multiply(a, inverse), nil
+}
+
+// the element whose bits are the coefficients of its polynomial
+func (field Gf2mBig) Element(i int64) *big.Int {
+	return big.NewInt(i)
+}
+
+// a uniformly random element from a cryptographically secure source
+func (field Gf2mBig) Random() (*big.Int, error) {
+	return field.RandomFrom(crand.Reader)
+}
+
+// a uniformly random element drawn from r
+func (field Gf2mBig) RandomFrom(r io.Reader) (*big.Int, error) {
+	return crand.Int(r, new(big.Int).Lsh(big.NewInt(1), uint(field.m)))
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	shamir "github.com/49pctber/shamir"
+	"github.com/spf13/cobra"
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check [shares...]",
+	Short: "check that shares agree with each other, without reconstructing the secret",
+	Long: `check that shares agree with each other, without reconstructing the secret
+
+Shares are given as arguments, or read from standard input if there are none.
+With more shares than the threshold, every share must lie on the same polynomial, so a share that was corrupted or forged is reported.
+The threshold recorded in the shares is used unless another is given with -k.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		disableCoreDumps()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+
+		input := strings.Join(args, "\n")
+		if len(args) == 0 {
+			data, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				log.Fatal(err)
+			}
+			input = string(data)
+		}
+
+		shares, err := parseShares(cmd, input)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(shares) == 0 {
+			fmt.Println("No valid shares specified. Exiting.")
+			return
+		}
+
+		k, _ := cmd.Flags().GetInt("threshold")
+
+		consistent := true
+		for id, shares := range groupShares(shares) {
+			if !checkShares(id, shares, k) {
+				consistent = false
+			}
+			for _, share := range shares {
+				share.Wipe()
+			}
+		}
+
+		if !consistent {
+			os.Exit(1)
+		}
+	},
+}
+
+// report whether the shares of one secret are consistent
+func checkShares(id string, shares []shamir.Share, k int) bool {
+	if k == 0 {
+		for _, share := range shares {
+			k = max(k, share.GetThreshold())
+		}
+	}
+	if k == 0 {
+		log.Fatalf("shares of %s don't record their threshold, so give it with -k", id)
+	}
+
+	err := shamir.CheckConsistency(shares, k)
+
+	var inconsistent *shamir.InconsistentSharesError
+	switch {
+	case err == nil && len(shares) == k:
+		fmt.Printf("%s: %d shares, exactly the threshold, so there is nothing to check against\n", id, len(shares))
+	case err == nil:
+		fmt.Printf("%s: all %d shares are consistent with threshold %d\n", id, len(shares), k)
+	case errors.As(err, &inconsistent):
+		fmt.Printf("%s: %v\n", id, err)
+		for _, share := range shares {
+			for _, x := range inconsistent.Disagreeing {
+				if share.GetXString() == fmt.Sprintf("%d", x) {
+					fmt.Printf("  %s\n", share.ShareLabel())
+				}
+			}
+		}
+		return false
+	default:
+		log.Fatalf("%s: %v", id, err)
+	}
+
+	return true
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+	checkCmd.Flags().String("format", "shamir", "format of the shares: shamir, vault (base64), or vault-hex")
+	checkCmd.Flags().IntP("threshold", "k", 0, "number of shares needed to reconstruct the secret (default: the threshold recorded in the shares)")
+	checkCmd.Flags().StringSliceP("identity", "i", nil, "age identity files or SSH ed25519 private keys used to decrypt encrypted shares")
+}
@@ -0,0 +1,6 @@
+//go:build !unix
+
+package cmd
+
+// core dumps are only disabled on Unix systems
+func disableCoreDumps() {}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// keep secrets out of core dumps if the process crashes
+func disableCoreDumps() {
+	err := unix.Setrlimit(unix.RLIMIT_CORE, &unix.Rlimit{Cur: 0, Max: 0})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not disable core dumps: %v\n", err)
+	}
+}
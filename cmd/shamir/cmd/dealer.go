@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"os"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// dealer keys are ordinary OpenSSH ed25519 keys, as made by ssh-keygen -t ed25519
+
+var ErrNotEd25519 error = errors.New("dealer keys must be ed25519 keys")
+
+// read an unencrypted OpenSSH ed25519 private key
+func readDealerKey(fname string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(fname)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ssh.ParseRawPrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+
+	priv, ok := key.(*ed25519.PrivateKey)
+	if !ok {
+		return nil, ErrNotEd25519
+	}
+
+	return *priv, nil
+}
+
+// read an ed25519 public key in authorized_keys format
+func readTrustedDealer(fname string) (ed25519.PublicKey, error) {
+	data, err := os.ReadFile(fname)
+	if err != nil {
+		return nil, err
+	}
+
+	key, _, _, _, err := ssh.ParseAuthorizedKey(data)
+	if err != nil {
+		return nil, err
+	}
+
+	cryptoKey, ok := key.(ssh.CryptoPublicKey)
+	if !ok {
+		return nil, ErrNotEd25519
+	}
+
+	pub, ok := cryptoKey.CryptoPublicKey().(ed25519.PublicKey)
+	if !ok {
+		return nil, ErrNotEd25519
+	}
+
+	return pub, nil
+}
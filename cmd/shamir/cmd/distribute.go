@@ -1,19 +1,25 @@
 package cmd
 
 import (
+	"bytes"
+	"crypto/ed25519"
 	"embed"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path"
 	"path/filepath"
-	"slices"
 	"strings"
 	"text/template"
+	"time"
 
+	"filippo.io/age"
 	"github.com/49pctber/shamir"
+	"github.com/49pctber/shamir/slip39"
 	"github.com/spf13/cobra"
 
 	"github.com/skip2/go-qrcode"
@@ -22,17 +28,52 @@ import (
 //go:embed template/*
 var templates embed.FS
 
-func parseInput(cmd *cobra.Command) (int, int, int, bool, bool, bool, bool) {
+// output formats requested on the command line
+type distributeOptions struct {
+	format     string
+	qr         bool
+	card       bool
+	file       bool
+	print      bool
+	armor      bool
+	holders    []string
+	slip39     bool
+	recipients []age.Recipient
+	wrap       bool
+	wrapped    []string // passphrase-wrapped share strings, or empty for unwrapped shares
+	dealerKey  ed25519.PrivateKey
+	primeField *shamir.Fp    // share the secret as an integer over GF(p) instead of byte by byte
+	privacy    int           // privacy threshold of packed shares, or 0 for ordinary shares
+	scheme     shamir.Scheme // disperse the secret into fragments instead of sharing it
+	xs         []int         // x coordinates of the shares, or empty for 1, ..., n
+	randomXs   bool
+	seed       []byte // derive the shares from this seed and the secret, so they can be regenerated
+}
+
+// the string to distribute for the ith share, which is wrapped if its holder chose a passphrase
+func (opts distributeOptions) shareString(i int, share shamir.Share) string {
+	if i < len(opts.wrapped) && opts.wrapped[i] != "" {
+		return opts.wrapped[i]
+	}
+	return share.String()
+}
+
+func parseInput(cmd *cobra.Command) (int, int, int, distributeOptions) {
 	invalid_command := false
 
+	var opts distributeOptions
+	opts.slip39, _ = cmd.Flags().GetBool("slip39")
+	groups, _ := cmd.Flags().GetString("slip39-groups")
+
+	// n and k describe the only group unless SLIP-39 groups are given explicitly
 	nshares, err := cmd.Flags().GetInt("nshares")
-	if err != nil || nshares < 2 {
+	if (err != nil || nshares < 2) && !(opts.slip39 && groups != "") {
 		fmt.Println("provide n >= 2")
 		invalid_command = true
 	}
 
 	threshold, err := cmd.Flags().GetInt("threshold")
-	if err != nil || threshold < 2 {
+	if (err != nil || threshold < 2) && !(opts.slip39 && groups != "") {
 		fmt.Println("provide k >= 2")
 		invalid_command = true
 	}
@@ -43,38 +84,326 @@ func parseInput(cmd *cobra.Command) (int, int, int, bool, bool, bool, bool) {
 		invalid_command = true
 	}
 
-	// check that primitivePoly is of primitive polynomial of degree 8
-	validpolynomials := []int{
-		0b100011101,
-		0b100101011,
-		0b101011111,
-		0b101100011,
-		0b101100101,
-		0b101101001,
-		0b111000011,
-		0b111100111,
+	if name, _ := cmd.Flags().GetString("field"); name != "" {
+		primitivePoly, err = shamir.FieldPolynomialByName(name)
+		if err != nil {
+			fmt.Printf("error reading field: %v\n", err)
+			invalid_command = true
+		}
+
+		if cmd.Flags().Changed("primitive") {
+			fmt.Println("--field and --primitive cannot be combined")
+			invalid_command = true
+		}
+	}
+
+	// each byte of the secret must be an element of the field, so the polynomial must be irreducible of degree 8
+	if shamir.ComputeDegree(primitivePoly) != 8 || !shamir.IsIrreducible(primitivePoly) {
+		fmt.Println("not an irreducible polynomial of degree 8, such as 0x11b or 0x11d")
+		invalid_command = true
+	}
+
+	opts.qr, _ = cmd.Flags().GetBool("qr")
+	opts.card, _ = cmd.Flags().GetBool("card")
+	opts.file, _ = cmd.Flags().GetBool("file")
+	opts.print, _ = cmd.Flags().GetBool("print")
+	opts.armor, _ = cmd.Flags().GetBool("armor")
+	opts.holders, _ = cmd.Flags().GetStringSlice("holders")
+
+	opts.format, _ = cmd.Flags().GetString("format")
+	switch opts.format {
+	case "shamir":
+	case "vault":
+		// vault can only combine shares over the AES polynomial, so another field can't be honored
+		if cmd.Flags().Changed("primitive") || cmd.Flags().Changed("field") {
+			fmt.Println("vault shares always use the AES polynomial, so --format vault cannot be combined with --primitive or --field")
+			invalid_command = true
+		}
+		if opts.qr || opts.card || opts.file || opts.print || opts.armor {
+			fmt.Println("vault shares can only be printed to the terminal")
+			invalid_command = true
+		}
+		primitivePoly = shamir.VaultPolynomial
+	case "ssss":
+		if opts.qr || opts.card || opts.file || opts.print || opts.armor {
+			fmt.Println("ssss shares can only be printed to the terminal")
+			invalid_command = true
+		}
+	default:
+		fmt.Println("format must be shamir, vault, or ssss")
+		invalid_command = true
 	}
 
-	if !slices.Contains(validpolynomials, primitivePoly) {
-		fmt.Println("not a primitive polynomial of degree 8")
-		fmt.Println("choose one of the following: ", validpolynomials)
+	if opts.slip39 && (opts.format != "shamir" || opts.qr || opts.card || opts.file || opts.print || opts.armor) {
+		fmt.Println("SLIP-39 mnemonics can only be printed to the terminal")
 		invalid_command = true
 	}
 
-	qr, _ := cmd.Flags().GetBool("qr")
-	card, _ := cmd.Flags().GetBool("card")
-	file, _ := cmd.Flags().GetBool("file")
-	print, _ := cmd.Flags().GetBool("print")
+	if recipientsFile, _ := cmd.Flags().GetString("recipients"); recipientsFile != "" {
+		opts.recipients, err = readRecipients(recipientsFile)
+		if err != nil {
+			fmt.Printf("error reading recipients: %v\n", err)
+			invalid_command = true
+		} else if len(opts.recipients) != nshares {
+			fmt.Printf("provide one recipient for each of the %d shares\n", nshares)
+			invalid_command = true
+		}
+
+		// anything else would expose the shares to whoever runs this command
+		if opts.format != "shamir" || opts.slip39 || opts.card || opts.print {
+			fmt.Println("encrypted shares can only be saved with --file, --armor, or --qr")
+			invalid_command = true
+		} else if !opts.file && !opts.armor && !opts.qr {
+			fmt.Println("choose --file, --armor, or --qr to save the encrypted shares")
+			invalid_command = true
+		}
+	}
+
+	if dealerKeyFile, _ := cmd.Flags().GetString("dealer-key"); dealerKeyFile != "" {
+		opts.dealerKey, err = readDealerKey(dealerKeyFile)
+		if err != nil {
+			fmt.Printf("error reading dealer key: %v\n", err)
+			invalid_command = true
+		}
+
+		if opts.format != "shamir" || opts.slip39 {
+			fmt.Println("only shamir shares can be signed by the dealer")
+			invalid_command = true
+		}
+	}
+
+	if name, _ := cmd.Flags().GetString("prime-field"); name != "" {
+		field, err := shamir.PrimeFieldByName(name)
+		if err != nil {
+			fmt.Printf("error reading prime field: %v\n", err)
+			invalid_command = true
+		}
+		opts.primeField = &field
+
+		if opts.format != "shamir" || opts.slip39 {
+			fmt.Println("--prime-field cannot be combined with --format or --slip39")
+			invalid_command = true
+		}
+	}
+
+	opts.privacy, _ = cmd.Flags().GetInt("privacy")
+	if opts.privacy != 0 {
+		if opts.privacy < 1 || opts.privacy >= threshold {
+			fmt.Println("provide 1 <= privacy threshold < k")
+			invalid_command = true
+		}
+
+		if opts.format != "shamir" || opts.slip39 || opts.primeField != nil || opts.dealerKey != nil || len(opts.recipients) > 0 || opts.qr || opts.card || opts.print || opts.armor {
+			fmt.Println("packed shares can only be printed to the terminal or saved with --file")
+			invalid_command = true
+		}
+	}
+
+	if name, _ := cmd.Flags().GetString("disperse"); name != "" {
+		opts.scheme, err = shamir.ParseScheme(name)
+		if err != nil {
+			fmt.Printf("error reading dispersal scheme: %v\n", err)
+			invalid_command = true
+		}
+
+		if opts.format != "shamir" || opts.slip39 || opts.primeField != nil || opts.dealerKey != nil || opts.privacy != 0 {
+			fmt.Println("--disperse cannot be combined with --format, --slip39, --prime-field, --dealer-key, or --privacy")
+			invalid_command = true
+		}
+	}
+
+	opts.xs, _ = cmd.Flags().GetIntSlice("x")
+	opts.randomXs, _ = cmd.Flags().GetBool("random-x")
+	if len(opts.xs) > 0 || opts.randomXs {
+		if len(opts.xs) > 0 && opts.randomXs {
+			fmt.Println("--x and --random-x cannot be combined")
+			invalid_command = true
+		}
+
+		if len(opts.xs) > 0 && len(opts.xs) != nshares {
+			fmt.Printf("provide one x coordinate for each of the %d shares\n", nshares)
+			invalid_command = true
+		}
+
+		if opts.format == "ssss" || opts.slip39 || opts.privacy != 0 {
+			fmt.Println("--x and --random-x cannot be combined with ssss, SLIP-39, or packed shares")
+			invalid_command = true
+		}
+	}
+
+	if seedFile, _ := cmd.Flags().GetString("seed-file"); seedFile != "" {
+		opts.seed, err = os.ReadFile(seedFile)
+		if err != nil {
+			fmt.Printf("error reading seed: %v\n", err)
+			invalid_command = true
+		} else if len(opts.seed) < shamir.MinSeedLen {
+			fmt.Printf("seed must be at least %d bytes\n", shamir.MinSeedLen)
+			invalid_command = true
+		}
+
+		if opts.format == "ssss" || opts.slip39 {
+			fmt.Println("--seed-file cannot be combined with ssss or SLIP-39")
+			invalid_command = true
+		}
+	}
+
+	opts.wrap, _ = cmd.Flags().GetBool("wrap")
+	if opts.wrap && (opts.format != "shamir" || opts.slip39 || opts.armor || len(opts.recipients) > 0 || opts.privacy != 0 || opts.scheme != shamir.SchemeShamir) {
+		fmt.Println("--wrap cannot be combined with --format, --slip39, --armor, --recipients, --privacy, or --disperse")
+		invalid_command = true
+	}
+
+	if len(opts.holders) > 0 && len(opts.holders) != nshares {
+		fmt.Printf("provide one holder for each of the %d shares\n", nshares)
+		invalid_command = true
+	}
 
 	if invalid_command {
 		log.Fatal("invalid command")
 	}
 
-	return nshares, threshold, primitivePoly, qr, card, file, print
+	return nshares, threshold, primitivePoly, opts
+}
+
+// share a secret so it can be combined by ssss-combine, printing the shares to the terminal
+func distributeSsss(secret []byte, nshares, threshold int) {
+	shares, err := shamir.SplitSsss("", nshares, threshold, secret)
+	if err != nil {
+		log.Fatalf("error distributing secret: %v\n", err)
+	}
+
+	fmt.Printf("Generating shares using a (%d,%d) scheme with a %d bit security level.\n", threshold, nshares, 8*len(secret))
+	for _, share := range shares {
+		fmt.Println(share)
+	}
+}
+
+// share a master secret as SLIP-39 mnemonics, printing each group's mnemonics to the terminal
+func distributeSlip39(cmd *cobra.Command, secret []byte, nshares, threshold int) {
+	groupThreshold, _ := cmd.Flags().GetInt("slip39-group-threshold")
+	passphrase, _ := cmd.Flags().GetString("slip39-passphrase")
+	exponent, _ := cmd.Flags().GetInt("slip39-exponent")
+	extendable, _ := cmd.Flags().GetBool("slip39-extendable")
+
+	groups := []slip39.GroupParameters{{MemberThreshold: threshold, MemberCount: nshares}}
+	if spec, _ := cmd.Flags().GetString("slip39-groups"); spec != "" {
+		groups = groups[:0]
+		for _, group := range strings.Split(spec, ",") {
+			var params slip39.GroupParameters
+			_, err := fmt.Sscanf(strings.TrimSpace(group), "%dof%d", &params.MemberThreshold, &params.MemberCount)
+			if err != nil {
+				log.Fatalf("groups must look like 2of3,3of5: %v\n", err)
+			}
+			groups = append(groups, params)
+		}
+	}
+
+	if groupThreshold == 0 {
+		groupThreshold = len(groups)
+	}
+
+	mnemonics, err := slip39.Generate(groupThreshold, groups, secret, []byte(passphrase), extendable, exponent)
+	if err != nil {
+		log.Fatalf("error distributing secret: %v\n", err)
+	}
+
+	fmt.Printf("Generating SLIP-39 mnemonics. Any %d of the following %d groups are needed.\n", groupThreshold, len(groups))
+	for i, group := range mnemonics {
+		fmt.Printf("Group %d (any %d of %d):\n", i+1, groups[i].MemberThreshold, groups[i].MemberCount)
+		for _, mnemonic := range group {
+			fmt.Println(mnemonic)
+		}
+	}
+}
+
+// share a secret as packed shares, printing them to the terminal and saving them if requested
+func distributePacked(secret []byte, primitivePoly, nshares, threshold int, opts distributeOptions) {
+	options := make([]shamir.Option, 0)
+	if opts.seed != nil {
+		options = append(options, shamir.WithSeed(opts.seed))
+	}
+
+	packed, err := shamir.NewPackedSecret(primitivePoly, nshares, opts.privacy, threshold, secret, options...)
+	if err != nil {
+		log.Fatalf("error distributing secret: %v\n", err)
+	}
+	defer packed.Wipe()
+
+	fmt.Printf("Secret %s\nCommitment %s\n", packed.GetId(), packed.GetCommitment())
+	fmt.Printf("Any %d shares reveal nothing, and any %d reconstruct the secret; shares in between reveal part of it.\nShares:\n", opts.privacy, threshold)
+	for _, share := range packed.GetShares() {
+		fmt.Printf("  %s\n", share)
+	}
+
+	if !opts.file {
+		return
+	}
+
+	for _, share := range packed.GetShares() {
+		fname, err := filepath.Abs(share.ShareLabel() + ".txt")
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if err := os.WriteFile(fname, []byte(share.String()), 0400); err != nil {
+			fmt.Println(err)
+			continue
+		}
+
+		fmt.Printf("%s: text saved to %s\n", share.ShareLabel(), fname)
+	}
+}
+
+// read one public key per line, ignoring blank lines and comments
+func readRecipients(fname string) ([]age.Recipient, error) {
+	data, err := os.ReadFile(fname)
+	if err != nil {
+		return nil, err
+	}
+
+	recipients := make([]age.Recipient, 0)
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		recipient, err := shamir.ParseRecipient(line)
+		if err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	return recipients, nil
 }
 
-func generateSecret(secret []byte, primitivePoly, nshares, threshold int) *shamir.Shamir {
-	s, err := shamir.NewShamirSecret(primitivePoly, nshares, threshold, secret)
+func generateSecret(secret []byte, primitivePoly, nshares, threshold int, opts distributeOptions) *shamir.Shamir {
+	options := make([]shamir.Option, 0)
+	if opts.dealerKey != nil {
+		options = append(options, shamir.WithDealerKey(opts.dealerKey))
+	}
+	if opts.primeField != nil {
+		options = append(options, shamir.WithPrimeField(*opts.primeField))
+	}
+	if opts.scheme != shamir.SchemeShamir {
+		options = append(options, shamir.WithScheme(opts.scheme))
+	}
+	if len(opts.xs) > 0 {
+		xs := make([]shamir.GfElement, len(opts.xs))
+		for i, x := range opts.xs {
+			xs[i] = shamir.GfElement(x)
+		}
+		options = append(options, shamir.WithXs(xs...))
+	}
+	if opts.randomXs {
+		options = append(options, shamir.WithRandomXs())
+	}
+	if opts.seed != nil {
+		options = append(options, shamir.WithSeed(opts.seed))
+	}
+
+	s, err := shamir.NewShamirSecret(primitivePoly, nshares, threshold, secret, options...)
 	if err != nil {
 		log.Fatalf("error distributing secret: %v\n", err)
 	}
@@ -82,14 +411,75 @@ func generateSecret(secret []byte, primitivePoly, nshares, threshold int) *shami
 	return s
 }
 
-func distributePNGs(s *shamir.Shamir) error {
-	for _, share := range s.GetShares() {
+// prompt for a passphrase for each share, wrapping the shares whose holders choose one
+func wrapShares(s *shamir.Shamir, opts *distributeOptions) {
+	fmt.Println("Enter a passphrase for each share, or leave it empty to leave that share unwrapped.")
+
+	opts.wrapped = make([]string, len(s.GetShares()))
+	for i, share := range s.GetShares() {
+		passphrase, err := readNewPassphrase(fmt.Sprintf("Passphrase for %s: ", share.ShareLabel()))
+		if err != nil {
+			log.Fatalf("error reading passphrase: %v\n", err)
+		}
+		if len(passphrase) == 0 {
+			continue
+		}
+
+		opts.wrapped[i], err = share.Wrap(passphrase)
+		shamir.Wipe(passphrase)
+		if err != nil {
+			log.Fatalf("error wrapping share: %v\n", err)
+		}
+	}
+}
+
+// print the shares to the terminal in the requested format
+func printShares(s *shamir.Shamir, opts distributeOptions) {
+	if opts.dealerKey != nil {
+		fmt.Printf("Shares signed by dealer %s\n", shamir.Fingerprint(opts.dealerKey.Public().(ed25519.PublicKey)))
+	}
+
+	if len(opts.recipients) > 0 {
+		fmt.Printf("Secret %s: %d shares encrypted to their recipients\n", s.GetId(), len(s.GetShares()))
+		fmt.Printf("Commitment %s\n", s.GetCommitment())
+		return
+	}
+
+	if opts.format != "vault" {
+		fmt.Printf("Secret %s\nCommitment %s\nShares:\n", s.GetId(), s.GetCommitment())
+		for i, share := range s.GetShares() {
+			fmt.Printf("  %s\n", opts.shareString(i, share))
+		}
+		return
+	}
+
+	fmt.Printf("Secret %s\nCommitment %s\n", s.GetId(), s.GetCommitment())
+	for i, share := range s.GetShares() {
+		key, err := share.VaultString()
+		if err != nil {
+			log.Fatalf("error encoding share for vault: %v\n", err)
+		}
+		fmt.Printf("Unseal Key %d: %s\n", i+1, key)
+	}
+}
+
+func distributePNGs(s *shamir.Shamir, opts distributeOptions) error {
+	for i, share := range s.GetShares() {
 		fname, err := filepath.Abs(share.ShareLabel() + ".png")
 		if err != nil {
 			return err
 		}
 
-		err = qrcode.WriteFile(share.String(), qrcode.High, -10, fname)
+		content := opts.shareString(i, share)
+		if len(opts.recipients) > 0 {
+			data, err := share.Encrypt(opts.recipients[i])
+			if err != nil {
+				return err
+			}
+			content = string(data)
+		}
+
+		err = qrcode.WriteFile(content, qrcode.High, -10, fname)
 		if err != nil {
 			return err
 		}
@@ -100,8 +490,8 @@ func distributePNGs(s *shamir.Shamir) error {
 	return nil
 }
 
-func distributeCards(s *shamir.Shamir) error {
-	for _, share := range s.GetShares() {
+func distributeCards(s *shamir.Shamir, opts distributeOptions) error {
+	for i, share := range s.GetShares() {
 		fname, err := filepath.Abs(share.ShareLabel() + ".svg")
 		if err != nil {
 			return err
@@ -117,7 +507,7 @@ func distributeCards(s *shamir.Shamir) error {
 			return err
 		}
 
-		q, err := qrcode.New(share.String(), qrcode.High)
+		q, err := qrcode.New(opts.shareString(i, share), qrcode.High)
 		if err != nil {
 			panic(err)
 		}
@@ -144,18 +534,43 @@ func distributeCards(s *shamir.Shamir) error {
 	return nil
 }
 
-func distributeFiles(s *shamir.Shamir) error {
+func distributeFiles(s *shamir.Shamir, opts distributeOptions) error {
 
 	dir, err := os.Getwd()
 	if err != nil {
 		return err
 	}
 
-	for _, share := range s.GetShares() {
+	created := time.Now()
+
+	for i, share := range s.GetShares() {
 
 		fname := filepath.Clean(path.Join(dir, fmt.Sprintf("%s.txt", share.ShareLabel())))
+		data := []byte(opts.shareString(i, share))
+
+		holder := ""
+		if i < len(opts.holders) {
+			holder = opts.holders[i]
+		}
 
-		err := os.WriteFile(fname, []byte(share.String()), 0400)
+		if opts.armor {
+			fname = filepath.Clean(path.Join(dir, fmt.Sprintf("%s.asc", share.ShareLabel())))
+			data = share.Armor(holder, created)
+		}
+
+		if len(opts.recipients) > 0 {
+			fname = filepath.Clean(path.Join(dir, fmt.Sprintf("%s.age", share.ShareLabel())))
+			if opts.armor {
+				data, err = share.EncryptArmor(opts.recipients[i:i+1], holder, created)
+			} else {
+				data, err = share.Encrypt(opts.recipients[i])
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		err := os.WriteFile(fname, data, 0400)
 		if err != nil {
 			fmt.Println(err)
 		}
@@ -175,11 +590,12 @@ type ShareData struct {
 }
 
 type TemplateData struct {
-	SecretID string
-	Shares   []ShareData
+	SecretID   string
+	Commitment string
+	Shares     []ShareData
 }
 
-func distributePrintablePage(s *shamir.Shamir) error {
+func distributePrintablePage(s *shamir.Shamir, opts distributeOptions) error {
 
 	if len(s.GetShares()) > 25 {
 		return errors.New("too many shares to print on one page")
@@ -200,7 +616,7 @@ func distributePrintablePage(s *shamir.Shamir) error {
 	sharedata := make([]ShareData, len(shares))
 	for i, share := range shares {
 
-		qrraw, err := qrcode.Encode(share.String(), qrcode.High, -5)
+		qrraw, err := qrcode.Encode(opts.shareString(i, share), qrcode.High, -5)
 		if err != nil {
 			return err
 		}
@@ -216,8 +632,9 @@ func distributePrintablePage(s *shamir.Shamir) error {
 	}
 
 	err = tmpl.ExecuteTemplate(outfile, "base.tmpl", TemplateData{
-		SecretID: s.GetId(),
-		Shares:   sharedata,
+		SecretID:   s.GetId(),
+		Commitment: s.GetCommitment(),
+		Shares:     sharedata,
 	})
 	if err != nil {
 		return err
@@ -227,30 +644,30 @@ func distributePrintablePage(s *shamir.Shamir) error {
 	return nil
 }
 
-func distribute(s *shamir.Shamir, qr, card, file, print bool) {
-	if qr {
-		err := distributePNGs(s)
+func distribute(s *shamir.Shamir, opts distributeOptions) {
+	if opts.qr {
+		err := distributePNGs(s, opts)
 		if err != nil {
 			fmt.Printf("error producing cards: %v\n", err)
 		}
 	}
 
-	if card {
-		err := distributeCards(s)
+	if opts.card {
+		err := distributeCards(s, opts)
 		if err != nil {
 			fmt.Printf("error producing cards: %v\n", err)
 		}
 	}
 
-	if file {
-		err := distributeFiles(s)
+	if opts.file || opts.armor {
+		err := distributeFiles(s, opts)
 		if err != nil {
 			fmt.Printf("error producing cards: %v\n", err)
 		}
 	}
 
-	if print {
-		err := distributePrintablePage(s)
+	if opts.print {
+		err := distributePrintablePage(s, opts)
 		if err != nil {
 			fmt.Printf("error producing printable SVG: %v\n", err)
 		}
@@ -261,6 +678,50 @@ var distributeCmd = &cobra.Command{
 	Use:   "distribute [secret to share]",
 	Short: "Distrbute a secret S into n shares, where any k shares can reconstruct S.",
 	Long:  `Distrbute a secret S into n shares, where any k shares can reconstruct S.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		disableCoreDumps()
+	},
+}
+
+// distribute a secret in the format given on the command line
+// SLIP-39 master secrets and secrets shared over a prime field are given in hex, as wallets display them, unless they are read from a file
+func distributeSecret(cmd *cobra.Command, secret []byte, hexSecret bool) {
+	nshares, threshold, primitivePoly, opts := parseInput(cmd)
+	defer shamir.Wipe(opts.seed)
+
+	if opts.format == "ssss" {
+		distributeSsss(secret, nshares, threshold)
+		return
+	}
+
+	if hexSecret && (opts.slip39 || opts.primeField != nil) {
+		decoded, err := hex.DecodeString(strings.TrimSpace(string(secret)))
+		if err != nil {
+			log.Fatalf("secret must be hex: %v\n", err)
+		}
+		defer shamir.Wipe(decoded)
+		secret = decoded
+	}
+
+	if opts.slip39 {
+		distributeSlip39(cmd, secret, nshares, threshold)
+		return
+	}
+
+	if opts.privacy != 0 {
+		distributePacked(secret, primitivePoly, nshares, threshold, opts)
+		return
+	}
+
+	s := generateSecret(secret, primitivePoly, nshares, threshold, opts)
+	defer s.Wipe()
+
+	if opts.wrap {
+		wrapShares(s, &opts)
+	}
+	printShares(s, opts)
+
+	distribute(s, opts)
 }
 
 var distributeFileCmd = &cobra.Command{
@@ -270,33 +731,79 @@ var distributeFileCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 
-		nshares, threshold, primitivePoly, qr, card, file, print := parseInput(cmd)
-
 		secret, err := os.ReadFile(args[0])
 		if err != nil {
 			log.Fatalf("error reading file: %v\n", err)
 		}
+		defer shamir.Wipe(secret)
 
-		s := generateSecret(secret, primitivePoly, nshares, threshold)
-		fmt.Println(s)
-
-		distribute(s, qr, card, file, print)
+		distributeSecret(cmd, secret, false)
 	},
 }
 
 var distributeStringCmd = &cobra.Command{
 	Use:   "string [secret]",
 	Short: "distributes a string",
-	Long:  `This is an example of sharing a secret string. The string is specified in the command, and then the shares are printed to the standard out.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `This is an example of sharing a secret string. The string is specified in the command, and then the shares are printed to the standard out.
+
+Note that the secret will be saved in your shell history. Use distribute prompt or distribute stdin to avoid this.`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 
-		nshares, threshold, primitivePoly, qr, card, file, print := parseInput(cmd)
+		secret := []byte(args[0])
+		defer shamir.Wipe(secret)
 
-		s := generateSecret([]byte(args[0]), primitivePoly, nshares, threshold)
-		fmt.Println(s)
+		distributeSecret(cmd, secret, true)
+	},
+}
 
-		distribute(s, qr, card, file, print)
+var distributeStdinCmd = &cobra.Command{
+	Use:   "stdin",
+	Short: "distributes a secret read from standard input",
+	Long: `distributes a secret read from standard input
+
+Every byte is shared, including any trailing newline, so use printf or echo -n when piping a string.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		secret, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			log.Fatalf("error reading secret: %v\n", err)
+		}
+		defer shamir.Wipe(secret)
+
+		distributeSecret(cmd, secret, true)
+	},
+}
+
+var distributePromptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "distributes a secret typed at a hidden prompt",
+	Long:  `distributes a secret typed at a hidden prompt, which is asked for twice to catch typos`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		secret, err := readHidden("Secret: ")
+		if err != nil {
+			log.Fatalf("error reading secret: %v\n", err)
+		}
+		defer shamir.Wipe(secret)
+
+		if len(secret) == 0 {
+			log.Fatal("secret cannot be empty")
+		}
+
+		confirmation, err := readHidden("Confirm secret: ")
+		if err != nil {
+			log.Fatalf("error reading secret: %v\n", err)
+		}
+		defer shamir.Wipe(confirmation)
+
+		if !bytes.Equal(secret, confirmation) {
+			log.Fatal("secrets do not match")
+		}
+
+		distributeSecret(cmd, secret, true)
 	},
 }
 
@@ -304,13 +811,37 @@ func init() {
 	rootCmd.AddCommand(distributeCmd)
 	distributeCmd.PersistentFlags().IntP("nshares", "n", 0, "number of shares to produce")
 	distributeCmd.PersistentFlags().IntP("threshold", "k", 0, "the number of shares needed to reconstruct the secret")
-	distributeCmd.PersistentFlags().IntP("primitive", "p", 0x11d, "primitive polynomial to use when constructing Galois field (must be of degree 8)")
+	distributeCmd.PersistentFlags().IntP("primitive", "p", 0x11d, "irreducible polynomial to use when constructing Galois field (must be of degree 8)")
+	distributeCmd.PersistentFlags().String("field", "", "named field to use instead of --primitive: aes (0x11b) or rs (0x11d), or a polynomial in hex")
+	distributeCmd.PersistentFlags().String("format", "shamir", "format in which to print shares: shamir, vault, or ssss")
 	distributeCmd.PersistentFlags().Bool("qr", false, "create PNG QR codes for each share")
 	distributeCmd.PersistentFlags().Bool("card", false, "create printable SVG cards for each share")
 	distributeCmd.PersistentFlags().Bool("file", false, "save each share in a separate txt file")
 	distributeCmd.PersistentFlags().Bool("print", false, "create a printable SVG file with QR codes for each share")
+	distributeCmd.PersistentFlags().Bool("armor", false, "save each share in a separate PEM-armored file with descriptive headers")
+	distributeCmd.PersistentFlags().StringSlice("holders", nil, "comma-separated names of the holder of each share, recorded in armored files")
+
+	distributeCmd.PersistentFlags().String("recipients", "", "file with one age or SSH ed25519 public key per line; each share is encrypted to the corresponding key")
+	distributeCmd.PersistentFlags().String("dealer-key", "", "OpenSSH ed25519 private key used to sign each share")
+	distributeCmd.PersistentFlags().Bool("wrap", false, "prompt for a passphrase to protect each share")
+	distributeCmd.PersistentFlags().String("prime-field", "", "share the secret as a single integer modulo a prime: p256, secp256k1, or p followed by a prime in hex (the string is a hex integer)")
+	distributeCmd.PersistentFlags().Int("privacy", 0, "make packed shares, each a fraction of the secret's size, where this many shares reveal nothing but fewer than k reveal part of the secret")
+	distributeCmd.PersistentFlags().String("disperse", "", "split the secret into fragments of about 1/k its size instead of sharing it: ida (no secrecy) or aont (secret unless the cipher is broken)")
+	distributeCmd.PersistentFlags().IntSlice("x", nil, "comma-separated x coordinates of the shares, such as employee IDs (default: 1, ..., n)")
+	distributeCmd.PersistentFlags().Bool("random-x", false, "place the shares at random x coordinates, so share numbers don't reveal how many shares exist")
+	distributeCmd.PersistentFlags().String("seed-file", "", "file whose bytes seed the shares, so the same secret and seed always give the same shares (keep it as safe as the secret)")
+	distributeCmd.PersistentFlags().Bool("slip39", false, "print SLIP-39 mnemonics instead of shares (the string is a hex master secret)")
+	distributeCmd.PersistentFlags().String("slip39-groups", "", "comma-separated SLIP-39 groups such as 2of3,3of5 (default: a single k of n group)")
+	distributeCmd.PersistentFlags().Int("slip39-group-threshold", 0, "number of SLIP-39 groups needed to reconstruct the secret (default: all groups)")
+	distributeCmd.PersistentFlags().String("slip39-passphrase", "", "passphrase used to encrypt the SLIP-39 master secret")
+	distributeCmd.PersistentFlags().Int("slip39-exponent", 1, "SLIP-39 iteration exponent, where decryption costs 10000*2^e PBKDF2 iterations")
+	distributeCmd.PersistentFlags().Bool("slip39-extendable", true, "allow more SLIP-39 groups to be added to the backup later")
 
 	distributeCmd.AddCommand(distributeFileCmd)
 
 	distributeCmd.AddCommand(distributeStringCmd)
+
+	distributeCmd.AddCommand(distributeStdinCmd)
+
+	distributeCmd.AddCommand(distributePromptCmd)
 }
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/49pctber/shamir"
+	"github.com/spf13/cobra"
+)
+
+var dkgCmd = &cobra.Command{
+	Use:   "dkg",
+	Short: "jointly generate a shared secret without a dealer",
+	Long: `jointly generate a shared secret without a dealer
+
+Each of the n participants runs "dkg round1" on their own machine, then copies the files it writes into a shared directory:
+the commitment file is public, and each share file is encrypted to the participant it is addressed to,
+using the age or SSH ed25519 public keys listed in the --recipients file, one per participant in index order.
+Once every file is in the shared directory, each participant runs "dkg finalize" with their identity (-i), which decrypts and checks the shares addressed to them and saves their share.
+Any k of the resulting shares can reconstruct the secret, which is never held by any single machine.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		disableCoreDumps()
+	},
+}
+
+var dkgRound1Cmd = &cobra.Command{
+	Use:   "round1",
+	Short: "choose this participant's contribution and write its commitment and shares",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		p, dir := dkgParticipant(cmd)
+		n, _ := cmd.Flags().GetInt("participants")
+
+		fname, _ := cmd.Flags().GetString("recipients")
+		recipients, err := readRecipients(fname)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(recipients) != n {
+			log.Fatalf("%s lists %d recipients, but there are %d participants", fname, len(recipients), n)
+		}
+
+		commitment, shares, err := p.Round1()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fname = dkgCommitmentFile(dir, commitment.Session, commitment.From)
+		if err := writeMessage(fname, commitment, 0644); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("commitment saved to %s (publish to every participant)\n", fname)
+
+		// each share is only ever written encrypted to the participant it is addressed to
+		for _, share := range shares {
+			data, err := share.Encrypt(recipients[share.To-1])
+			shamir.Wipe(share.Value)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			fname := dkgShareFile(dir, share.Session, share.From, share.To)
+			if err := os.WriteFile(fname, data, 0644); err != nil {
+				log.Fatal(err)
+			}
+			fmt.Printf("share saved to %s (encrypted to participant %d)\n", fname, share.To)
+		}
+	},
+}
+
+var dkgFinalizeCmd = &cobra.Command{
+	Use:   "finalize",
+	Short: "check every participant's messages and save this participant's share",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		p, dir := dkgParticipant(cmd)
+		session, _ := cmd.Flags().GetString("session")
+		n, _ := cmd.Flags().GetInt("participants")
+
+		identities, err := readIdentities(cmd)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		commitments := make([]shamir.DkgCommitment, 0, n)
+		shares := make([]shamir.DkgShare, 0, n)
+		for i := 1; i <= n; i++ {
+			var commitment shamir.DkgCommitment
+			if err := readMessage(dkgCommitmentFile(dir, session, i), &commitment); err != nil {
+				log.Fatal(err)
+			}
+			commitments = append(commitments, commitment)
+
+			fname := dkgShareFile(dir, session, i, p.GetIndex())
+			data, err := os.ReadFile(fname)
+			if err != nil {
+				log.Fatal(err)
+			}
+			share, err := shamir.DecryptDkgShare(data, identities...)
+			if err != nil {
+				log.Fatalf("%s: %v", fname, err)
+			}
+			shares = append(shares, share)
+		}
+
+		share, publicKey, err := p.Finalize(commitments, shares)
+		for _, s := range shares {
+			shamir.Wipe(s.Value)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer share.Wipe()
+
+		fname := filepath.Clean(path.Join(dir, fmt.Sprintf("%s.txt", share.ShareLabel())))
+		if err := os.WriteFile(fname, []byte(share.String()), 0400); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%s: text saved to %s\n", share.ShareLabel(), fname)
+		fmt.Printf("Public key: %s\n", hex.EncodeToString(publicKey))
+
+		// the share files addressed to this participant are no longer needed
+		for i := 1; i <= n; i++ {
+			os.Remove(dkgShareFile(dir, session, i, p.GetIndex()))
+		}
+	},
+}
+
+func dkgParticipant(cmd *cobra.Command) (*shamir.DkgParticipant, string) {
+	session, _ := cmd.Flags().GetString("session")
+	index, _ := cmd.Flags().GetInt("index")
+	n, _ := cmd.Flags().GetInt("participants")
+	k, _ := cmd.Flags().GetInt("threshold")
+	dir, _ := cmd.Flags().GetString("directory")
+
+	p, err := shamir.NewDkgParticipant(session, index, n, k)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return p, dir
+}
+
+func dkgCommitmentFile(dir, session string, from int) string {
+	return filepath.Clean(path.Join(dir, fmt.Sprintf("dkg-%s-commit-%d.json", session, from)))
+}
+
+func dkgShareFile(dir, session string, from, to int) string {
+	return filepath.Clean(path.Join(dir, fmt.Sprintf("dkg-%s-share-%d-to-%d.age", session, from, to)))
+}
+
+// round messages are exchanged as JSON files
+func writeMessage(fname string, message any, perm os.FileMode) error {
+	data, err := json.MarshalIndent(message, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fname, data, perm)
+}
+
+func readMessage(fname string, message any) error {
+	data, err := os.ReadFile(fname)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, message)
+}
+
+func init() {
+	rootCmd.AddCommand(dkgCmd)
+	dkgCmd.PersistentFlags().String("session", "", "ID of the key generation session, shared by every participant")
+	dkgCmd.PersistentFlags().Int("index", 0, "this participant's index, from 1 to n")
+	dkgCmd.PersistentFlags().IntP("participants", "n", 0, "number of participants")
+	dkgCmd.PersistentFlags().IntP("threshold", "k", 0, "number of participants needed to reconstruct the secret")
+	dkgCmd.PersistentFlags().StringP("directory", "d", ".", "directory where round messages are exchanged")
+	dkgCmd.MarkPersistentFlagRequired("session")
+	dkgCmd.MarkPersistentFlagRequired("index")
+	dkgCmd.MarkPersistentFlagRequired("participants")
+	dkgCmd.MarkPersistentFlagRequired("threshold")
+
+	dkgCmd.AddCommand(dkgRound1Cmd)
+	dkgRound1Cmd.Flags().String("recipients", "", "file with one age or SSH ed25519 public key per line, for participants 1 to n in order, which each share is encrypted to")
+	dkgRound1Cmd.MarkFlagRequired("recipients")
+
+	dkgCmd.AddCommand(dkgFinalizeCmd)
+	dkgFinalizeCmd.Flags().StringSliceP("identity", "i", nil, "age identity files or SSH ed25519 private keys used to decrypt the shares addressed to this participant")
+	dkgFinalizeCmd.MarkFlagRequired("identity")
+}
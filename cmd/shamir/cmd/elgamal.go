@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/49pctber/shamir"
+	"github.com/spf13/cobra"
+)
+
+var elgamalCmd = &cobra.Command{
+	Use:   "elgamal",
+	Short: "encrypt files that any k key holders can jointly decrypt, without reassembling the key",
+	Long: `encrypt files that any k key holders can jointly decrypt, without reassembling the key
+
+Key shares come from "shamir elgamal keygen" or "shamir dkg".
+Anyone can encrypt a file to the public key.
+To decrypt it, each holder runs "elgamal partial" on the ciphertext and passes on the partial decryption file it writes,
+and once there are k of them, "elgamal combine" decrypts the file.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		disableCoreDumps()
+	},
+}
+
+var elgamalKeygenCmd = &cobra.Command{
+	Use:   "keygen",
+	Short: "generate a decryption key split into shares",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		id, _ := cmd.Flags().GetString("id")
+		n, _ := cmd.Flags().GetInt("nshares")
+		k, _ := cmd.Flags().GetInt("threshold")
+		dir, _ := cmd.Flags().GetString("directory")
+
+		shares, publicKey, err := shamir.GenerateThresholdKey(id, n, k)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		for _, share := range shares {
+			fname := filepath.Clean(path.Join(dir, fmt.Sprintf("%s.txt", share.ShareLabel())))
+			if err := os.WriteFile(fname, []byte(share.String()), 0400); err != nil {
+				log.Fatal(err)
+			}
+			fmt.Printf("%s: text saved to %s\n", share.ShareLabel(), fname)
+			share.Wipe()
+		}
+		fmt.Printf("Public key: %s\n", hex.EncodeToString(publicKey))
+	},
+}
+
+var elgamalEncryptCmd = &cobra.Command{
+	Use:   "encrypt <file>",
+	Short: "encrypt a file to a shared public key",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+
+		hexKey, _ := cmd.Flags().GetString("public-key")
+		publicKey, err := hex.DecodeString(hexKey)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		message, err := os.ReadFile(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		ciphertext, err := shamir.EncryptToThresholdKey(publicKey, message)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		output, _ := cmd.Flags().GetString("output")
+		if output == "" {
+			output = args[0] + ".shamir"
+		}
+		if err := os.WriteFile(output, ciphertext, 0644); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("ciphertext saved to %s\n", output)
+	},
+}
+
+var elgamalPartialCmd = &cobra.Command{
+	Use:   "partial <ciphertext>",
+	Short: "compute this holder's partial decryption of a file",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+
+		dir, _ := cmd.Flags().GetString("directory")
+		ciphertext, err := os.ReadFile(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		share, err := readKeyShare(cmd)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer share.Wipe()
+
+		partial, err := shamir.PartialDecrypt(share, ciphertext)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fname := partialDecryptionFile(dir, args[0], fmt.Sprint(partial.Index))
+		if err := writeMessage(fname, partial, 0644); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("partial decryption saved to %s\n", fname)
+	},
+}
+
+var elgamalCombineCmd = &cobra.Command{
+	Use:   "combine <ciphertext>",
+	Short: "decrypt a file from the partial decryptions in a directory",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+
+		dir, _ := cmd.Flags().GetString("directory")
+		ciphertext, err := os.ReadFile(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fnames, err := filepath.Glob(partialDecryptionFile(dir, args[0], "*"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Found %d partial decryptions.\n", len(fnames))
+
+		partials := make([]shamir.PartialDecryption, len(fnames))
+		for i, fname := range fnames {
+			if err := readMessage(fname, &partials[i]); err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		message, err := shamir.CombineDecryption(ciphertext, partials)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer shamir.Wipe(message)
+
+		output, _ := cmd.Flags().GetString("output")
+		if output == "" {
+			output = strings.TrimSuffix(args[0], ".shamir")
+			if output == args[0] {
+				output += ".decrypted"
+			}
+		}
+		if err := os.WriteFile(output, message, 0600); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("plaintext saved to %s\n", output)
+	},
+}
+
+// partial decryptions are named after the ciphertext they decrypt
+func partialDecryptionFile(dir, ciphertext, index string) string {
+	return filepath.Clean(path.Join(dir, fmt.Sprintf("%s.partial-%s.json", filepath.Base(ciphertext), index)))
+}
+
+func init() {
+	rootCmd.AddCommand(elgamalCmd)
+
+	elgamalCmd.AddCommand(elgamalKeygenCmd)
+	elgamalKeygenCmd.Flags().String("id", "", "ID of the key shares")
+	elgamalKeygenCmd.Flags().IntP("nshares", "n", 0, "number of shares")
+	elgamalKeygenCmd.Flags().IntP("threshold", "k", 0, "number of shares needed to decrypt")
+	elgamalKeygenCmd.Flags().StringP("directory", "d", ".", "directory to save the shares to")
+	elgamalKeygenCmd.MarkFlagRequired("id")
+
+	elgamalCmd.AddCommand(elgamalEncryptCmd)
+	elgamalEncryptCmd.Flags().String("public-key", "", "hex-encoded public key of the shared key")
+	elgamalEncryptCmd.Flags().StringP("output", "o", "", "file to save the ciphertext to (default: <file>.shamir)")
+	elgamalEncryptCmd.MarkFlagRequired("public-key")
+
+	elgamalCmd.AddCommand(elgamalPartialCmd)
+	elgamalPartialCmd.Flags().String("share", "", "file containing this holder's key share")
+	elgamalPartialCmd.Flags().StringSliceP("identity", "i", nil, "age identity files or SSH ed25519 private keys used to decrypt an encrypted key share")
+	elgamalPartialCmd.Flags().StringP("directory", "d", ".", "directory to save the partial decryption to")
+	elgamalPartialCmd.MarkFlagRequired("share")
+
+	elgamalCmd.AddCommand(elgamalCombineCmd)
+	elgamalCombineCmd.Flags().StringP("directory", "d", ".", "directory containing the partial decryptions")
+	elgamalCombineCmd.Flags().StringP("output", "o", "", "file to save the plaintext to (default: the ciphertext's name without .shamir)")
+}
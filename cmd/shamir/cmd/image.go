@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/49pctber/shamir"
+	"github.com/makiuchi-d/gozxing"
+	multiqr "github.com/makiuchi-d/gozxing/multi/qrcode"
+	zxingqr "github.com/makiuchi-d/gozxing/qrcode"
+	"github.com/spf13/cobra"
+)
+
+var ErrNoQRCode error = errors.New("no QR code found in image")
+
+// matches the base64-encoded PNGs embedded in the card and printable page SVGs
+var svgImageRegexp = regexp.MustCompile(`href="data:image/png;base64,([\w\+\/=]+)"`)
+
+// reports whether a file should be read as an image instead of as text
+func isImageFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png", ".jpg", ".jpeg", ".svg":
+		return true
+	}
+	return false
+}
+
+// surround an image with a white border
+// cards are generated without a quiet zone, which the decoder needs to locate the QR code
+func addQuietZone(img image.Image) image.Image {
+	bounds := img.Bounds()
+	margin := max(bounds.Dx(), bounds.Dy()) / 8
+
+	padded := image.NewRGBA(image.Rect(0, 0, bounds.Dx()+2*margin, bounds.Dy()+2*margin))
+	draw.Draw(padded, padded.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(padded, bounds.Sub(bounds.Min).Add(image.Pt(margin, margin)), img, bounds.Min, draw.Over)
+
+	return padded
+}
+
+// decode the text of every QR code found in an image
+func decodeQRImage(img image.Image) ([]string, error) {
+	bmp, err := gozxing.NewBinaryBitmapFromImage(addQuietZone(img))
+	if err != nil {
+		return nil, err
+	}
+
+	hints := map[gozxing.DecodeHintType]interface{}{gozxing.DecodeHintType_TRY_HARDER: true}
+
+	texts := make([]string, 0)
+	results, err := multiqr.NewQRCodeMultiReader().DecodeMultiple(bmp, hints)
+	if err == nil {
+		for _, result := range results {
+			texts = append(texts, result.GetText())
+		}
+		return texts, nil
+	}
+
+	// the multi reader is stricter about finder patterns, so fall back to looking for a single code
+	result, err := zxingqr.NewQRCodeReader().Decode(bmp, hints)
+	if err != nil {
+		return nil, ErrNoQRCode
+	}
+
+	return append(texts, result.GetText()), nil
+}
+
+// decode the QR codes embedded as PNGs in an SVG produced by --card or --print
+func decodeQRSVG(data []byte) ([]string, error) {
+	texts := make([]string, 0)
+
+	for _, match := range svgImageRegexp.FindAllSubmatch(data, -1) {
+		raw, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(string(match[1]), "="))
+		if err != nil {
+			return nil, err
+		}
+
+		img, _, err := image.Decode(bytes.NewReader(raw))
+		if err != nil {
+			return nil, err
+		}
+
+		new_texts, err := decodeQRImage(img)
+		if err != nil {
+			return nil, err
+		}
+
+		texts = append(texts, new_texts...)
+	}
+
+	if len(texts) == 0 {
+		return nil, ErrNoQRCode
+	}
+
+	return texts, nil
+}
+
+// read the shares stored as QR codes in a PNG, JPEG, or SVG file
+func readSharesFromImage(cmd *cobra.Command, path string) ([]shamir.Share, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var texts []string
+	if strings.ToLower(filepath.Ext(path)) == ".svg" {
+		texts, err = decodeQRSVG(data)
+	} else {
+		var img image.Image
+		img, _, err = image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		texts, err = decodeQRImage(img)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return parseShares(cmd, strings.Join(texts, "\n"))
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"strings"
+	"testing"
+
+	"github.com/49pctber/shamir"
+	"github.com/skip2/go-qrcode"
+)
+
+// the shares to encode as QR codes
+func testShares(t *testing.T) []string {
+	s, err := shamir.NewShamirSecret(0x11d, 3, 2, []byte("decoded from a picture"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	texts := make([]string, 0)
+	for _, share := range s.GetShares() {
+		texts = append(texts, share.String())
+	}
+	return texts
+}
+
+func TestDecodeQRImage(t *testing.T) {
+	text := testShares(t)[0]
+
+	bordered, err := qrcode.Encode(text, qrcode.High, -10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// cards are generated without a border, so the decoder has to add the quiet zone itself
+	q, err := qrcode.New(text, qrcode.High)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.DisableBorder = true
+	borderless, err := q.PNG(-10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, data := range map[string][]byte{"bordered": bordered, "borderless": borderless} {
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		texts, err := decodeQRImage(img)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(texts) != 1 || texts[0] != text {
+			t.Errorf("%s: have %q, want %q", name, texts, text)
+		}
+	}
+
+	if _, err := decodeQRImage(image.NewGray(image.Rect(0, 0, 64, 64))); err != ErrNoQRCode {
+		t.Errorf("have %v, want %v", err, ErrNoQRCode)
+	}
+}
+
+func TestDecodeQRSVG(t *testing.T) {
+	texts := testShares(t)
+
+	// a printable page embeds each share as a data URI, like the --print template
+	var svg bytes.Buffer
+	svg.WriteString(`<svg xmlns="http://www.w3.org/2000/svg">`)
+	for i, text := range texts {
+		data, err := qrcode.Encode(text, qrcode.High, -5)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the decoder accepts both unpadded and padded base64
+		encoded := base64.RawStdEncoding.EncodeToString(data)
+		if i%2 == 1 {
+			encoded = base64.StdEncoding.EncodeToString(data)
+		}
+		fmt.Fprintf(&svg, `<image x="%d" y="0" href="data:image/png;base64,%s"/>`, 200*i, encoded)
+	}
+	svg.WriteString(`</svg>`)
+
+	decoded, err := decodeQRSVG(svg.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != len(texts) {
+		t.Fatalf("decoded %d QR codes, not %d", len(decoded), len(texts))
+	}
+	for i := range texts {
+		if decoded[i] != texts[i] {
+			t.Errorf("have %q, want %q", decoded[i], texts[i])
+		}
+	}
+
+	shares, err := shamir.NewSharesFromString(strings.Join(decoded, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := shamir.RecoverSecret(shares); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := decodeQRSVG([]byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`)); err != ErrNoQRCode {
+		t.Errorf("have %v, want %v", err, ErrNoQRCode)
+	}
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/49pctber/shamir"
+	"github.com/spf13/cobra"
+)
+
+var reconstructInteractiveCmd = &cobra.Command{
+	Use:   "interactive",
+	Short: "reconstruct secret from shares entered one at a time as custodians arrive",
+	Long: `reconstruct secret from shares entered one at a time as custodians arrive
+
+Each share is typed at a hidden prompt and checked immediately.
+Once the threshold is reached, the secret is reconstructed.
+If the shares don't record their threshold, give it with -k, or enter an empty line once every custodian has entered their share.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		options, err := recoverOptions(cmd)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		shares := collectShares(cmd)
+		if len(shares) == 0 {
+			fmt.Println("No shares entered. Exiting.")
+			return
+		}
+
+		secret, err := shamir.RecoverSecretBuffer(shares, options...)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer secret.Close()
+
+		fmt.Printf("%s:\n", shares[0].GetSecretId())
+		writeSecret(secret, shares[0].GetGroup() != "")
+		fmt.Println()
+
+		for _, share := range shares {
+			share.Wipe()
+		}
+	},
+}
+
+// prompt for shares until the threshold is reached, or until an empty line if the threshold is unknown
+func collectShares(cmd *cobra.Command) []shamir.Share {
+	shares := make([]shamir.Share, 0)
+	k, _ := cmd.Flags().GetInt("threshold")
+
+	for {
+		threshold := k
+		for _, share := range shares {
+			threshold = max(threshold, share.GetThreshold())
+		}
+
+		if threshold > 0 && len(shares) >= threshold {
+			return shares
+		}
+
+		line, err := readHidden(fmt.Sprintf("Share %d: ", len(shares)+1))
+		if err != nil {
+			if threshold > 0 {
+				log.Fatalf("input ended with %d of %d shares collected\n", len(shares), threshold)
+			}
+			return shares
+		}
+		if len(line) == 0 {
+			if threshold > 0 {
+				fmt.Printf("%d of %d collected; the secret can't be reconstructed until %d more are entered\n", len(shares), threshold, threshold-len(shares))
+				continue
+			}
+			return shares
+		}
+
+		new_shares, err := parseShares(cmd, string(line))
+		if err != nil {
+			fmt.Printf("Rejected: %v\n", err)
+			continue
+		}
+		if len(new_shares) != 1 {
+			fmt.Println("Rejected: enter exactly one share")
+			continue
+		}
+
+		// each share must be compatible with those already collected
+		if err := shamir.ValidateShares(append(shares, new_shares[0])); err != nil {
+			fmt.Printf("Rejected %s: %v\n", new_shares[0].ShareLabel(), err)
+			continue
+		}
+
+		shares = append(shares, new_shares[0])
+		threshold = max(threshold, new_shares[0].GetThreshold())
+
+		if threshold > 0 {
+			fmt.Printf("Accepted %s: %d of %d collected\n", new_shares[0].ShareLabel(), len(shares), threshold)
+		} else {
+			fmt.Printf("Accepted %s: %d collected (threshold unknown, enter an empty line when done)\n", new_shares[0].ShareLabel(), len(shares))
+		}
+	}
+}
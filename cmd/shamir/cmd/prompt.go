@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+var ErrPassphraseMismatch error = errors.New("passphrases do not match")
+
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// read a line from the terminal without echoing it, or from standard input if it is not a terminal
+func readHidden(prompt string) ([]byte, error) {
+	fmt.Fprint(os.Stderr, prompt)
+
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		line, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		return line, err
+	}
+
+	line, err := stdinReader.ReadString('\n')
+	if err != nil && line == "" {
+		return nil, err
+	}
+	return []byte(strings.TrimRight(line, "\r\n")), nil
+}
+
+// read a passphrase for a share
+func readPassphrase(label string) ([]byte, error) {
+	return readHidden(fmt.Sprintf("Passphrase for %s: ", label))
+}
+
+// read a new passphrase, asking for it twice to catch typos
+func readNewPassphrase(prompt string) ([]byte, error) {
+	passphrase, err := readHidden(prompt)
+	if err != nil || len(passphrase) == 0 {
+		return passphrase, err
+	}
+
+	confirmation, err := readHidden("Confirm passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(passphrase, confirmation) {
+		return nil, ErrPassphraseMismatch
+	}
+
+	return passphrase, nil
+}
@@ -1,14 +1,19 @@
 package cmd
 
 import (
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"filippo.io/age"
 	shamir "github.com/49pctber/shamir"
+	"github.com/49pctber/shamir/slip39"
 	"github.com/spf13/cobra"
 )
 
@@ -16,6 +21,9 @@ var reconstructCmd = &cobra.Command{
 	Use:   "reconstruct",
 	Short: "reconstruct secret",
 	Long:  `reconstruct secret based on strings or files`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		disableCoreDumps()
+	},
 }
 
 var reconstructFileCmd = &cobra.Command{
@@ -25,6 +33,7 @@ var reconstructFileCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 
 		shares := make([]shamir.Share, 0)
+		packedShares := make([]shamir.PackedShare, 0)
 
 		dir, err := cmd.Flags().GetString("directory")
 		if err != nil {
@@ -55,14 +64,36 @@ var reconstructFileCmd = &cobra.Command{
 				return nil
 			}
 
-			data, err := os.ReadFile(path)
-			if err != nil {
-				return err
-			}
-
-			new_shares, err := shamir.NewSharesFromString(string(data))
-			if err != nil {
-				return err
+			var new_shares []shamir.Share
+			if isImageFile(path) {
+				new_shares, err = readSharesFromImage(cmd, path)
+				if err != nil {
+					fmt.Printf("Skipping %s: %v\n", path, err)
+					return nil
+				}
+			} else {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+
+				if shamir.IsPacked(string(data)) {
+					new_packed, err := shamir.NewPackedSharesFromString(string(data))
+					if err != nil {
+						return err
+					}
+					packedShares = append(packedShares, new_packed...)
+					return nil
+				}
+
+				new_shares, err = parseShares(cmd, string(data))
+				if err != nil && shamir.IsEncrypted(string(data)) {
+					// shares encrypted to other holders
+					fmt.Printf("Skipping %s: %v\n", path, err)
+					return nil
+				} else if err != nil {
+					return err
+				}
 			}
 
 			shares = append(shares, new_shares...)
@@ -73,56 +104,86 @@ var reconstructFileCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
-		if len(shares) == 0 {
+		if len(shares)+len(packedShares) == 0 {
 			fmt.Println("No shares found. Exiting.")
 			return
 		} else {
-			fmt.Printf("Found %d shares.\n", len(shares))
+			fmt.Printf("Found %d shares.\n", len(shares)+len(packedShares))
 		}
 
-		secretDict := make(map[string][]shamir.Share, 0)
-
-		for _, share := range shares {
-			if _, ok := secretDict[share.GetSecretId()]; !ok {
-				secretDict[share.GetSecretId()] = make([]shamir.Share, 0)
-			}
-			secretDict[share.GetSecretId()] = append(secretDict[share.GetSecretId()], share)
+		options, err := recoverOptions(cmd)
+		if err != nil {
+			log.Fatal(err)
 		}
 
+		secretDict := groupShares(shares)
+
 		fmt.Println("Attempting to reconstruct secrets from shares that were found...")
 
 		for id, shares := range secretDict {
-			secret, err := shamir.RecoverSecret(shares)
+			secret, err := shamir.RecoverSecretBuffer(shares, options...)
 			if err != nil {
 				log.Fatal(err)
 			}
 
-			fname := "secret-" + id
-			abs, err := filepath.Abs(fname)
+			err = saveSecret(id, secret.Bytes())
+			secret.Close()
 			if err != nil {
 				log.Fatal(err)
 			}
+		}
 
-			err = os.WriteFile(fname, secret, 0700)
+		for id, shares := range groupShares(packedShares) {
+			secret, err := recoverPacked(cmd, shares)
 			if err != nil {
 				log.Fatal(err)
 			}
 
-			fmt.Printf("Secret %s saved to %s\n", id, abs)
+			err = saveSecret(id, secret.Bytes())
+			secret.Close()
+			if err != nil {
+				log.Fatal(err)
+			}
 		}
 	},
 }
 
+// save the secret to a file named after its ID
+func saveSecret(id string, secret []byte) error {
+	fname := "secret-" + id
+	abs, err := filepath.Abs(fname)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(fname, secret, 0700); err != nil {
+		return err
+	}
+
+	fmt.Printf("Secret %s saved to %s\n", id, abs)
+	return nil
+}
+
 var reconstructStringCmd = &cobra.Command{
 	Use:   "string [shares...]",
 	Short: "reconstruct secret given a sequences of shares",
 	Long:  `reconstruct secret given a sequences of shares`,
 	Run: func(cmd *cobra.Command, args []string) {
 
+		if format, _ := cmd.Flags().GetString("format"); format == "ssss" {
+			combineSsss(cmd, args)
+			return
+		}
+
+		if input := strings.Join(args, "\n"); shamir.IsPacked(input) {
+			printPackedSecrets(cmd, input)
+			return
+		}
+
 		shares := make([]shamir.Share, 0)
 
 		for _, arg := range args {
-			new_shares, err := shamir.NewSharesFromString(arg)
+			new_shares, err := parseShares(cmd, arg)
 			if err != nil {
 				log.Fatal(err)
 			}
@@ -135,33 +196,366 @@ var reconstructStringCmd = &cobra.Command{
 			return
 		}
 
-		secretDict := make(map[string][]shamir.Share, 0)
+		printSecrets(cmd, shares)
+	},
+}
+
+var reconstructImageCmd = &cobra.Command{
+	Use:   "image [images...]",
+	Short: "reconstruct secret from PNG, JPEG, or SVG images of QR codes",
+	Long: `reconstruct secret from PNG, JPEG, or SVG images of QR codes
+
+Accepts the PNGs produced by --qr, the SVGs produced by --card and --print, and photos or scans of any of them.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
 
-		for _, share := range shares {
-			fmt.Printf("Found %s\n", share.String())
-			if _, ok := secretDict[share.GetSecretId()]; !ok {
-				secretDict[share.GetSecretId()] = make([]shamir.Share, 0)
+		shares := make([]shamir.Share, 0)
+
+		for _, arg := range args {
+			new_shares, err := readSharesFromImage(cmd, arg)
+			if err != nil {
+				log.Fatalf("error reading %s: %v\n", arg, err)
 			}
-			secretDict[share.GetSecretId()] = append(secretDict[share.GetSecretId()], share)
+
+			shares = append(shares, new_shares...)
 		}
 
-		fmt.Println("Attempting to reconstruct secrets from shares that were found...")
+		if len(shares) == 0 {
+			fmt.Println("No valid shares found. Exiting.")
+			return
+		}
 
-		for id, shares := range secretDict {
-			secret, err := shamir.RecoverSecret(shares)
-			if err != nil {
+		printSecrets(cmd, shares)
+	},
+}
+
+var reconstructSlip39Cmd = &cobra.Command{
+	Use:   "slip39 [mnemonics...]",
+	Short: "reconstruct a master secret from SLIP-39 mnemonics",
+	Long: `reconstruct a master secret from SLIP-39 mnemonics
+
+Each mnemonic is given as a single quoted argument. The master secret is printed in hex.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+
+		passphrase, _ := cmd.Flags().GetString("passphrase")
+
+		combined, err := slip39.Combine(args, []byte(passphrase))
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		secret, err := shamir.NewSecretBufferFrom(combined)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer secret.Close()
+
+		fmt.Print("Master secret: ")
+		writeSecret(secret, true)
+		fmt.Println()
+	},
+}
+
+var reconstructStdinCmd = &cobra.Command{
+	Use:   "stdin",
+	Short: "reconstruct secret from shares read from standard input",
+	Long:  `reconstruct secret from shares read from standard input`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		input, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		reconstructInput(cmd, string(input))
+	},
+}
+
+var reconstructPromptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "reconstruct secret from shares typed at a hidden prompt",
+	Long:  `reconstruct secret from shares typed at a hidden prompt, one per line, ending with an empty line`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		lines := make([]string, 0)
+		for i := 1; ; i++ {
+			line, err := readHidden(fmt.Sprintf("Share %d (empty to finish): ", i))
+			if err != nil && len(lines) == 0 {
 				log.Fatal(err)
 			}
-			fmt.Printf("%s:\n%s\n", id, secret)
+			if err != nil || len(line) == 0 {
+				break
+			}
+			lines = append(lines, string(line))
 		}
+
+		reconstructInput(cmd, strings.Join(lines, "\n"))
 	},
 }
 
+// reconstruct the secrets from shares in the format given on the command line
+func reconstructInput(cmd *cobra.Command, input string) {
+	if format, _ := cmd.Flags().GetString("format"); format == "ssss" {
+		combineSsss(cmd, []string{input})
+		return
+	}
+
+	if shamir.IsPacked(input) {
+		printPackedSecrets(cmd, input)
+		return
+	}
+
+	shares, err := parseShares(cmd, input)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if len(shares) == 0 {
+		fmt.Println("No valid shares specified. Exiting.")
+		return
+	}
+
+	printSecrets(cmd, shares)
+}
+
+// combine shares produced by ssss-split
+func combineSsss(cmd *cobra.Command, args []string) {
+	shares, err := shamir.NewSsssSharesFromString(strings.Join(args, "\n"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	threshold, _ := cmd.Flags().GetInt("threshold")
+	if threshold == 0 {
+		threshold = len(shares)
+	}
+
+	noDiffusion, _ := cmd.Flags().GetBool("no-diffusion")
+
+	combined, err := shamir.CombineSsss(shares, threshold, !noDiffusion)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	secret, err := shamir.NewSecretBufferFrom(combined)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer secret.Close()
+
+	fmt.Print("Resulting secret: ")
+	writeSecret(secret, false)
+	fmt.Println()
+}
+
+// parse shares in the format given on the command line
+func parseShares(cmd *cobra.Command, input string) ([]shamir.Share, error) {
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return nil, err
+	}
+
+	if shamir.IsEncrypted(input) {
+		identities, err := readIdentities(cmd)
+		if err != nil {
+			return nil, err
+		}
+		return shamir.DecryptShares(input, identities...)
+	}
+
+	if shamir.IsWrapped(input) {
+		return shamir.UnwrapShares(input, readPassphrase)
+	}
+
+	switch format {
+	case "shamir":
+		return shamir.NewSharesFromString(input)
+	case "vault":
+		return shamir.NewSharesFromVaultString(shamir.VaultSecretId, input)
+	case "vault-hex":
+		return shamir.NewSharesFromVaultHexString(shamir.VaultSecretId, input)
+	default:
+		return nil, fmt.Errorf("unknown share format %s", format)
+	}
+}
+
+// read the identity files given on the command line, used to decrypt shares encrypted to its public key
+func readIdentities(cmd *cobra.Command) ([]age.Identity, error) {
+	fnames, err := cmd.Flags().GetStringSlice("identity")
+	if err != nil {
+		return nil, err
+	}
+	if len(fnames) == 0 {
+		return nil, errors.New("shares are encrypted, so provide an identity file with --identity")
+	}
+
+	identities := make([]age.Identity, 0)
+	for _, fname := range fnames {
+		data, err := os.ReadFile(fname)
+		if err != nil {
+			return nil, err
+		}
+
+		new_identities, err := shamir.ParseIdentities(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fname, err)
+		}
+		identities = append(identities, new_identities...)
+	}
+
+	return identities, nil
+}
+
+// options for RecoverSecret given on the command line
+func recoverOptions(cmd *cobra.Command) ([]shamir.Option, error) {
+	fnames, err := cmd.Flags().GetStringSlice("trusted-dealer")
+	if err != nil {
+		return nil, err
+	}
+
+	opts := make([]shamir.Option, 0)
+	for _, fname := range fnames {
+		pub, err := readTrustedDealer(fname)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, shamir.WithTrustedDealer(pub))
+	}
+
+	commitment, err := cmd.Flags().GetString("commitment")
+	if err != nil {
+		return nil, err
+	}
+	if commitment != "" {
+		opts = append(opts, shamir.WithCommitment(commitment))
+	}
+
+	return opts, nil
+}
+
+// sort shares by secret ID, ignoring shares that were found more than once
+func groupShares[S interface {
+	String() string
+	GetSecretId() string
+}](shares []S) map[string][]S {
+	secretDict := make(map[string][]S, 0)
+	seen := make(map[string]any, 0)
+
+	for _, share := range shares {
+		if _, ok := seen[share.String()]; ok {
+			continue
+		}
+		seen[share.String()] = nil
+
+		secretDict[share.GetSecretId()] = append(secretDict[share.GetSecretId()], share)
+	}
+
+	return secretDict
+}
+
+// reconstruct each secret and print it to the terminal
+func printSecrets(cmd *cobra.Command, shares []shamir.Share) {
+	options, err := recoverOptions(cmd)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, share := range shares {
+		fmt.Printf("Found %s\n", share.ShareLabel())
+	}
+
+	secretDict := groupShares(shares)
+
+	fmt.Println("Attempting to reconstruct secrets from shares that were found...")
+
+	for id, shares := range secretDict {
+		secret, err := shamir.RecoverSecretBuffer(shares, options...)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Printf("%s:\n", id)
+		writeSecret(secret, shares[0].GetGroup() != "")
+		fmt.Println()
+		secret.Close()
+	}
+}
+
+// reconstruct each secret shared by the packed shares in the input and print it to the terminal
+func printPackedSecrets(cmd *cobra.Command, input string) {
+	shares, err := shamir.NewPackedSharesFromString(input)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, share := range shares {
+		fmt.Printf("Found %s\n", share.ShareLabel())
+	}
+
+	fmt.Println("Attempting to reconstruct secrets from shares that were found...")
+
+	for id, shares := range groupShares(shares) {
+		secret, err := recoverPacked(cmd, shares)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Printf("%s:\n", id)
+		writeSecret(secret, false)
+		fmt.Println()
+		secret.Close()
+	}
+}
+
+// recover a secret from packed shares into a locked buffer, which can only be checked against a commitment
+func recoverPacked(cmd *cobra.Command, shares []shamir.PackedShare) (*shamir.SecretBuffer, error) {
+	options, err := recoverOptions(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := shamir.RecoverPackedSecret(shares, options...)
+	if err != nil {
+		return nil, err
+	}
+	return shamir.NewSecretBufferFrom(secret)
+}
+
+// write the secret directly from its buffer, so no formatted copies are left behind
+// secrets such as integers and master seeds are written in hex, since they're rarely printable
+func writeSecret(secret *shamir.SecretBuffer, hexEncode bool) {
+	if hexEncode {
+		hex.NewEncoder(os.Stdout).Write(secret.Bytes())
+	} else {
+		os.Stdout.Write(secret.Bytes())
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(reconstructCmd)
+	reconstructCmd.PersistentFlags().String("format", "shamir", "format of the shares: shamir, vault (base64), vault-hex, or ssss")
+	reconstructCmd.PersistentFlags().IntP("threshold", "k", 0, "number of shares needed to reconstruct an ssss secret, or to stop an interactive session (default: all shares given)")
+	reconstructCmd.PersistentFlags().StringSliceP("identity", "i", nil, "age identity files or SSH ed25519 private keys used to decrypt encrypted shares")
+	reconstructCmd.PersistentFlags().StringSlice("trusted-dealer", nil, "OpenSSH ed25519 public keys of trusted dealers; shares not signed by one of them are rejected")
+	reconstructCmd.PersistentFlags().String("commitment", "", "commitment published when the secret was distributed; the secret is only output if it matches")
+	reconstructCmd.PersistentFlags().Bool("no-diffusion", false, "skip the ssss diffusion layer, like ssss-combine -D")
 
 	reconstructCmd.AddCommand(reconstructFileCmd)
 	reconstructFileCmd.PersistentFlags().StringP("directory", "d", "", "directory to search and save results")
 
 	reconstructCmd.AddCommand(reconstructStringCmd)
+
+	reconstructCmd.AddCommand(reconstructImageCmd)
+
+	reconstructCmd.AddCommand(reconstructStdinCmd)
+
+	reconstructCmd.AddCommand(reconstructPromptCmd)
+
+	reconstructCmd.AddCommand(reconstructInteractiveCmd)
+
+	reconstructCmd.AddCommand(reconstructSlip39Cmd)
+	reconstructSlip39Cmd.Flags().String("passphrase", "", "passphrase used to encrypt the SLIP-39 master secret")
 }
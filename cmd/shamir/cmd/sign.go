@@ -0,0 +1,356 @@
+package cmd
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"filippo.io/age"
+
+	"github.com/49pctber/shamir"
+	"github.com/spf13/cobra"
+)
+
+var signCmd = &cobra.Command{
+	Use:   "sign",
+	Short: "sign a message with shares of an ed25519 key, without reconstructing the key",
+	Long: `sign a message with shares of an ed25519 key, without reconstructing the key
+
+Key shares come from "shamir dkg" or "shamir sign split".
+Each signer runs "sign round1" and publishes its commitment to the session directory, then runs "sign round2" once every signer's commitment is there.
+The nonces from round 1 are written to a private --nonces file outside the session directory, and are deleted in round 2.
+Anyone can then run "sign aggregate" to combine the signature shares into an ordinary ed25519 signature.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		disableCoreDumps()
+	},
+}
+
+var signSplitCmd = &cobra.Command{
+	Use:   "split",
+	Short: "split an existing OpenSSH ed25519 private key into signing shares",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		fname, _ := cmd.Flags().GetString("key")
+		id, _ := cmd.Flags().GetString("id")
+		n, _ := cmd.Flags().GetInt("nshares")
+		k, _ := cmd.Flags().GetInt("threshold")
+		dir, _ := cmd.Flags().GetString("directory")
+
+		key, err := readDealerKey(fname)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		shares, err := shamir.SplitSigningKey(id, key, n, k)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		for _, share := range shares {
+			fname := filepath.Clean(path.Join(dir, fmt.Sprintf("%s.txt", share.ShareLabel())))
+			if err := os.WriteFile(fname, []byte(share.String()), 0400); err != nil {
+				log.Fatal(err)
+			}
+			fmt.Printf("%s: text saved to %s\n", share.ShareLabel(), fname)
+			share.Wipe()
+		}
+		fmt.Printf("Public key: %s\n", hex.EncodeToString(key.Public().(ed25519.PublicKey)))
+	},
+}
+
+var signRound1Cmd = &cobra.Command{
+	Use:   "round1",
+	Short: "generate this signer's nonces and publish its commitment",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		session, dir := signSession(cmd)
+		noncesFile := signNoncesFile(cmd, dir)
+		share, err := readKeyShare(cmd)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer share.Wipe()
+
+		nonces, commitment, err := shamir.SignRound1(share)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer nonces.Wipe()
+
+		// never overwrite nonces that may still be waiting for round 2
+		f, err := os.OpenFile(noncesFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err != nil {
+			log.Fatal(err)
+		}
+		data := nonces.Bytes()
+		_, err = f.Write(data)
+		shamir.Wipe(data)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(noncesFile)
+			log.Fatal(err)
+		}
+		fmt.Printf("nonces saved to %s (keep this one private until round 2)\n", noncesFile)
+
+		fname := signCommitmentFile(dir, session, commitment.Index)
+		if err := writeMessage(fname, commitment, 0644); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("commitment saved to %s (publish to every signer)\n", fname)
+	},
+}
+
+var signRound2Cmd = &cobra.Command{
+	Use:   "round2",
+	Short: "sign the message once every signer has published its commitment",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		session, dir := signSession(cmd)
+		noncesFile := signNoncesFile(cmd, dir)
+		publicKey, message := signMessage(cmd)
+		share, err := readKeyShare(cmd)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer share.Wipe()
+
+		index, err := strconv.Atoi(share.GetXString())
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// the nonces are removed before signing, so they can never be used twice
+		data, err := os.ReadFile(noncesFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := os.Remove(noncesFile); err != nil {
+			log.Fatal(err)
+		}
+		nonces, err := shamir.ParseSigningNonces(data)
+		shamir.Wipe(data)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		commitments, err := readSigningCommitments(dir, session)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		signatureShare, err := shamir.SignRound2(share, nonces, publicKey, message, commitments)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fname := signShareFile(dir, session, index)
+		if err := writeMessage(fname, signatureShare, 0644); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("signature share saved to %s\n", fname)
+	},
+}
+
+var signAggregateCmd = &cobra.Command{
+	Use:   "aggregate",
+	Short: "combine the signature shares into an ed25519 signature",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		session, dir := signSession(cmd)
+		publicKey, message := signMessage(cmd)
+
+		commitments, err := readSigningCommitments(dir, session)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fnames, err := filepath.Glob(signShareFile(dir, session, -1))
+		if err != nil {
+			log.Fatal(err)
+		}
+		shares := make([]shamir.SignatureShare, len(fnames))
+		for i, fname := range fnames {
+			if err := readMessage(fname, &shares[i]); err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		signature, err := shamir.AggregateSignature(publicKey, message, commitments, shares)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		output, _ := cmd.Flags().GetString("output")
+		if output != "" {
+			if err := os.WriteFile(output, signature, 0644); err != nil {
+				log.Fatal(err)
+			}
+			fmt.Printf("signature saved to %s\n", output)
+		}
+		fmt.Printf("Signature: %s\n", hex.EncodeToString(signature))
+	},
+}
+
+func signSession(cmd *cobra.Command) (string, string) {
+	session, _ := cmd.Flags().GetString("session")
+	dir, _ := cmd.Flags().GetString("directory")
+
+	if err := shamir.ValidateSession(session); err != nil {
+		log.Fatal(err)
+	}
+
+	return session, dir
+}
+
+// the group public key and the message to sign, given on the command line
+func signMessage(cmd *cobra.Command) ([]byte, []byte) {
+	hexKey, _ := cmd.Flags().GetString("public-key")
+	fname, _ := cmd.Flags().GetString("message")
+
+	publicKey, err := hex.DecodeString(hexKey)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	message, err := os.ReadFile(fname)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return publicKey, message
+}
+
+// read this signer's key share, which may be wrapped or encrypted
+func readKeyShare(cmd *cobra.Command) (shamir.Share, error) {
+	fname, _ := cmd.Flags().GetString("share")
+	data, err := os.ReadFile(fname)
+	if err != nil {
+		return shamir.Share{}, err
+	}
+	defer shamir.Wipe(data)
+
+	var shares []shamir.Share
+	switch input := string(data); {
+	case shamir.IsEncrypted(input):
+		var identities []age.Identity
+		identities, err = readIdentities(cmd)
+		if err == nil {
+			shares, err = shamir.DecryptShares(input, identities...)
+		}
+	case shamir.IsWrapped(input):
+		shares, err = shamir.UnwrapShares(input, readPassphrase)
+	default:
+		shares, err = shamir.NewSharesFromString(input)
+	}
+	if err != nil {
+		return shamir.Share{}, err
+	}
+
+	if len(shares) != 1 || shares[0].GetGroup() != shamir.GroupEd25519 {
+		return shamir.Share{}, errors.New("the share file must contain exactly one ed25519 key share")
+	}
+	return shares[0], nil
+}
+
+func readSigningCommitments(dir, session string) ([]shamir.SigningCommitment, error) {
+	fnames, err := filepath.Glob(signCommitmentFile(dir, session, -1))
+	if err != nil {
+		return nil, err
+	}
+
+	commitments := make([]shamir.SigningCommitment, len(fnames))
+	for i, fname := range fnames {
+		if err := readMessage(fname, &commitments[i]); err != nil {
+			return nil, err
+		}
+	}
+	return commitments, nil
+}
+
+// session file names, where an index of -1 matches every signer
+func signFile(dir, session, kind string, index int) string {
+	name := fmt.Sprintf("sign-%s-%s-%d", session, kind, index)
+	if index < 0 {
+		name = fmt.Sprintf("sign-%s-%s-*", session, kind)
+	}
+	return filepath.Clean(path.Join(dir, name))
+}
+
+// the private file holding this signer's nonces between rounds, which must not be in the shared directory
+func signNoncesFile(cmd *cobra.Command, dir string) string {
+	fname, _ := cmd.Flags().GetString("nonces")
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	absFile, err := filepath.Abs(fname)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if rel, err := filepath.Rel(absDir, absFile); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		log.Fatal("the nonces file must be outside the session directory, where other signers could read it")
+	}
+
+	return fname
+}
+
+func signCommitmentFile(dir, session string, index int) string {
+	return signFile(dir, session, "commit", index) + ".json"
+}
+
+func signShareFile(dir, session string, index int) string {
+	return signFile(dir, session, "share", index) + ".json"
+}
+
+func init() {
+	rootCmd.AddCommand(signCmd)
+	signCmd.PersistentFlags().String("session", "", "ID of the signing session, shared by every signer")
+	signCmd.PersistentFlags().StringP("directory", "d", ".", "directory where round messages are exchanged")
+
+	signCmd.AddCommand(signSplitCmd)
+	signSplitCmd.Flags().String("key", "", "OpenSSH ed25519 private key to split")
+	signSplitCmd.Flags().String("id", "", "ID of the key shares")
+	signSplitCmd.Flags().IntP("nshares", "n", 0, "number of shares")
+	signSplitCmd.Flags().IntP("threshold", "k", 0, "number of shares needed to sign")
+	signSplitCmd.MarkFlagRequired("key")
+	signSplitCmd.MarkFlagRequired("id")
+
+	signCmd.AddCommand(signRound1Cmd)
+	signRound1Cmd.Flags().String("share", "", "file containing this signer's key share")
+	signRound1Cmd.Flags().StringSliceP("identity", "i", nil, "age identity files or SSH ed25519 private keys used to decrypt an encrypted key share")
+	signRound1Cmd.Flags().String("nonces", "", "private file to save this signer's nonces to, outside the session directory")
+	signRound1Cmd.MarkFlagRequired("share")
+	signRound1Cmd.MarkFlagRequired("nonces")
+
+	signCmd.AddCommand(signRound2Cmd)
+	signRound2Cmd.Flags().String("share", "", "file containing this signer's key share")
+	signRound2Cmd.Flags().StringSliceP("identity", "i", nil, "age identity files or SSH ed25519 private keys used to decrypt an encrypted key share")
+	signRound2Cmd.Flags().String("public-key", "", "hex-encoded public key of the shared key")
+	signRound2Cmd.Flags().String("message", "", "file containing the message to sign")
+	signRound2Cmd.Flags().String("nonces", "", "private file the nonces were saved to in round 1, which is deleted")
+	signRound2Cmd.MarkFlagRequired("share")
+	signRound2Cmd.MarkFlagRequired("nonces")
+	signRound2Cmd.MarkFlagRequired("public-key")
+	signRound2Cmd.MarkFlagRequired("message")
+
+	signCmd.AddCommand(signAggregateCmd)
+	signAggregateCmd.Flags().String("public-key", "", "hex-encoded public key of the shared key")
+	signAggregateCmd.Flags().String("message", "", "file containing the message that was signed")
+	signAggregateCmd.Flags().StringP("output", "o", "", "file to save the raw 64-byte signature to")
+	signAggregateCmd.MarkFlagRequired("public-key")
+	signAggregateCmd.MarkFlagRequired("message")
+}
@@ -0,0 +1,78 @@
+package shamir
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strings"
+)
+
+// a commitment is a salted hash of the secret, published when it is distributed,
+// so witnesses can confirm the right secret was reconstructed without seeing it
+// commitments look like sha256:<base64 salt>:<base64 hash>
+
+const commitmentSaltLen int = 16
+
+var ErrInvalidCommitment error = errors.New("commitment must look like sha256:<salt>:<hash>")
+var ErrCommitmentMismatch error = errors.New("recovered secret does not match the commitment")
+
+func commitmentHash(salt, secret []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte("shamir-commitment"))
+	h.Write(salt)
+	h.Write(secret)
+	return h.Sum(nil)
+}
+
+// NewCommitment commits to the secret with a random salt
+func NewCommitment(secret []byte) (string, error) {
+	return newCommitment(rand.Reader, secret)
+}
+
+// commit to the secret with a salt drawn from r
+func newCommitment(r io.Reader, secret []byte) (string, error) {
+	salt := make([]byte, commitmentSaltLen)
+	if _, err := io.ReadFull(r, salt); err != nil {
+		return "", err
+	}
+
+	return strings.Join([]string{
+		"sha256",
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(commitmentHash(salt, secret)),
+	}, ":"), nil
+}
+
+// VerifyRecovered checks that a recovered secret matches the commitment published when it was distributed
+func VerifyRecovered(secret []byte, commitment string) error {
+	parts := strings.Split(strings.TrimSpace(commitment), ":")
+	if len(parts) != 3 || parts[0] != "sha256" {
+		return ErrInvalidCommitment
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ErrInvalidCommitment
+	}
+
+	hash, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil || len(hash) != sha256.Size {
+		return ErrInvalidCommitment
+	}
+
+	if subtle.ConstantTimeCompare(hash, commitmentHash(salt, secret)) != 1 {
+		return ErrCommitmentMismatch
+	}
+
+	return nil
+}
+
+// WithCommitment makes RecoverSecret check the recovered secret against a published commitment
+func WithCommitment(commitment string) Option {
+	return func(o *options) {
+		o.commitment = commitment
+	}
+}
@@ -0,0 +1,49 @@
+package shamir
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommitment(t *testing.T) {
+	secret := []byte("witnessed without being seen")
+
+	s, err := NewShamirSecret(0x11d, 3, 2, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commitment := s.GetCommitment()
+	if !strings.Contains(s.String(), commitment) {
+		t.Fatal("commitment is missing from the summary")
+	}
+
+	if err := VerifyRecovered(secret, commitment); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyRecovered([]byte("some other secret"), commitment); err != ErrCommitmentMismatch {
+		t.Errorf("have %v, want %v", err, ErrCommitmentMismatch)
+	}
+
+	// the same secret gets a different commitment each time, so it can't be guessed from the commitment
+	other, err := NewCommitment(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == commitment {
+		t.Error("commitment is not salted")
+	}
+
+	if _, err := RecoverSecret(s.GetShares()[:2], WithCommitment(commitment)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RecoverSecret(s.GetShares()[:1], WithCommitment(commitment)); err != ErrCommitmentMismatch {
+		t.Errorf("have %v, want %v", err, ErrCommitmentMismatch)
+	}
+
+	for _, invalid := range []string{"", "md5:abc:def", "sha256:abc", "sha256:!!:abc"} {
+		if err := VerifyRecovered(secret, invalid); err != ErrInvalidCommitment {
+			t.Errorf("%q: have %v, want %v", invalid, err, ErrInvalidCommitment)
+		}
+	}
+}
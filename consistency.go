@@ -0,0 +1,140 @@
+package shamir
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var ErrInvalidThreshold error = errors.New("threshold must be at least 1")
+var ErrTooFewShares error = errors.New("fewer shares than the threshold")
+
+// InconsistentSharesError reports shares that don't lie on the polynomial agreed on by the most shares
+type InconsistentSharesError struct {
+	Disagreeing []GfElement // x coordinates of the shares off the polynomial, empty if ambiguous
+	Ambiguous   bool        // no single polynomial is agreed on by the most shares, so the bad shares can't be identified
+}
+
+func (err *InconsistentSharesError) Error() string {
+	if err.Ambiguous {
+		return "shares are inconsistent, and too few agree to tell which are wrong"
+	}
+
+	xs := make([]string, len(err.Disagreeing))
+	for i, x := range err.Disagreeing {
+		xs[i] = fmt.Sprintf("%d", x)
+	}
+	return fmt.Sprintf("shares at x = %s don't lie on the same polynomial as the others", strings.Join(xs, ", "))
+}
+
+// CheckConsistency confirms that the shares all lie on a single polynomial of degree k-1, without reconstructing the secret
+// with exactly k shares there's nothing to check, since any k points lie on such a polynomial
+// otherwise it returns an *InconsistentSharesError naming the shares that disagree with the most others
+// finding them can mean trying every k of the shares, which is slow when there are many shares and many disagree
+func CheckConsistency(shares []Share, k int) error {
+	if err := ValidateShares(shares); err != nil {
+		return err
+	}
+	if k < 1 {
+		return ErrInvalidThreshold
+	}
+	if len(shares) < k {
+		return ErrTooFewShares
+	}
+
+	n := len(shares)
+	var best []bool
+	bestCount, tied := 0, false
+
+	// try each k of the shares, counting how many shares lie on the polynomial through them
+	subset := make([]Share, k)
+	indices := make([]int, k)
+	for i := range indices {
+		indices[i] = i
+	}
+	for {
+		members := make([]bool, n)
+		for i, index := range indices {
+			subset[i] = shares[index]
+			members[index] = true
+		}
+
+		count := k
+		for i, share := range shares {
+			if members[i] {
+				continue
+			}
+			ok, err := agrees(subset, share)
+			if err != nil {
+				return err
+			}
+			if ok {
+				members[i] = true
+				count++
+			}
+		}
+
+		switch {
+		case count > bestCount:
+			best, bestCount, tied = members, count, false
+		case count == bestCount && !slices.Equal(members, best):
+			tied = true
+		}
+
+		// two polynomials of degree k-1 share at most k-1 points,
+		// so a set of shares this large can't be matched by any other
+		if 2*bestCount > n+k-1 {
+			break
+		}
+
+		if !nextCombination(indices, n) {
+			break
+		}
+	}
+
+	if bestCount == n {
+		return nil
+	}
+	if tied {
+		return &InconsistentSharesError{Ambiguous: true}
+	}
+
+	disagreeing := make([]GfElement, 0, n-bestCount)
+	for i, share := range shares {
+		if !best[i] {
+			disagreeing = append(disagreeing, share.x)
+		}
+	}
+	return &InconsistentSharesError{Disagreeing: disagreeing}
+}
+
+// whether the share lies on the polynomial through the subset
+func agrees(subset []Share, share Share) (bool, error) {
+	expected, err := Interpolate(subset, share.x)
+	if err != nil {
+		return false, err
+	}
+	defer expected.Wipe()
+
+	return slices.Equal(expected.y, share.y), nil
+}
+
+// advance indices to the next k-combination of 0, ..., n-1 in lexicographic order
+// returns false once every combination has been visited
+func nextCombination(indices []int, n int) bool {
+	k := len(indices)
+	i := k - 1
+	for i >= 0 && indices[i] == n-k+i {
+		i--
+	}
+	if i < 0 {
+		return false
+	}
+
+	indices[i]++
+	for j := i + 1; j < k; j++ {
+		indices[j] = indices[j-1] + 1
+	}
+	return true
+}
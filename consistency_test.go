@@ -0,0 +1,78 @@
+package shamir
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// a copy of the share with one byte of its y value changed
+func tamper(share Share, i int) Share {
+	share.y = slices.Clone(share.y)
+	share.y[i] ^= 1
+	return share
+}
+
+func TestCheckConsistency(t *testing.T) {
+	s, err := NewShamirSecret(0x11b, 6, 3, []byte("consistent"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares := s.GetShares()
+
+	if err := CheckConsistency(shares, 3); err != nil {
+		t.Errorf("honest shares should be consistent, got %v", err)
+	}
+	if err := CheckConsistency(shares[:3], 3); err != nil {
+		t.Errorf("exactly k shares should be consistent, got %v", err)
+	}
+
+	// all six shares lie on a degree 2 polynomial, but not a degree 1 polynomial
+	var inconsistent *InconsistentSharesError
+	if err := CheckConsistency(shares, 2); !errors.As(err, &inconsistent) {
+		t.Errorf("shares of a 3-of-6 secret shouldn't be consistent with threshold 2, got %v", err)
+	}
+
+	bad := slices.Clone(shares)
+	bad[1] = tamper(bad[1], 4)
+	bad[4] = tamper(bad[4], 0)
+
+	err = CheckConsistency(bad, 3)
+	if !errors.As(err, &inconsistent) {
+		t.Fatalf("tampered shares should be inconsistent, got %v", err)
+	}
+	if inconsistent.Ambiguous || !slices.Equal(inconsistent.Disagreeing, []GfElement{2, 5}) {
+		t.Errorf("shares 2 and 5 should disagree, got %v", err)
+	}
+
+	// with four shares and one wrong, any three of them define a polynomial through only those three
+	err = CheckConsistency(bad[:4], 3)
+	if !errors.As(err, &inconsistent) || !inconsistent.Ambiguous {
+		t.Errorf("one bad share of four should be ambiguous, got %v", err)
+	}
+
+	if err := CheckConsistency(shares[:2], 3); err != ErrTooFewShares {
+		t.Errorf("fewer than k shares should fail, got %v", err)
+	}
+	if err := CheckConsistency(shares, 0); err != ErrInvalidThreshold {
+		t.Errorf("threshold 0 should fail, got %v", err)
+	}
+}
+
+func TestCheckConsistencyScalar(t *testing.T) {
+	shares, _, err := GenerateThresholdKey("CHECK", 5, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CheckConsistency(shares, 2); err != nil {
+		t.Errorf("honest shares should be consistent, got %v", err)
+	}
+
+	shares[0] = tamper(shares[0], 0)
+	var inconsistent *InconsistentSharesError
+	err = CheckConsistency(shares, 2)
+	if !errors.As(err, &inconsistent) || !slices.Equal(inconsistent.Disagreeing, []GfElement{1}) {
+		t.Errorf("share 1 should disagree, got %v", err)
+	}
+}
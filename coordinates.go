@@ -0,0 +1,95 @@
+package shamir
+
+import (
+	crand "crypto/rand"
+	"errors"
+	"io"
+	"math"
+	"math/big"
+)
+
+// shares are at x = 1, ..., n unless the caller chooses their x coordinates
+// random x coordinates hide how many shares exist and keep share numbers unpredictable
+
+var ErrZeroX error = errors.New("a share at x = 0 would be the secret itself")
+var ErrWrongNumberOfXs error = errors.New("provide one x coordinate for each share")
+var ErrTooManyRandomXs error = errors.New("more shares than nonzero elements of the field")
+
+// WithXs places the shares at the given distinct, nonzero x coordinates, such as employee IDs
+func WithXs(xs ...GfElement) Option {
+	return func(o *options) {
+		o.xs = append([]GfElement{}, xs...)
+	}
+}
+
+// WithRandomXs places the shares at random distinct, nonzero x coordinates
+func WithRandomXs() Option {
+	return func(o *options) {
+		o.randomXs = true
+	}
+}
+
+// the x coordinates of nshares shares in a field with size elements, drawing random ones from r
+func (o options) shareXs(nshares int, size *big.Int, r io.Reader) ([]GfElement, error) {
+	// x coordinates are stored as ints, and random ones are kept short enough to write down
+	limit := int64(math.MaxInt64)
+	if size.IsInt64() {
+		limit = size.Int64()
+	}
+	randomLimit := min(limit, math.MaxUint16+1)
+
+	if len(o.xs) > 0 {
+		if len(o.xs) != nshares {
+			return nil, ErrWrongNumberOfXs
+		}
+
+		seen := make(map[GfElement]any, nshares)
+		for _, x := range o.xs {
+			if x == 0 {
+				return nil, ErrZeroX
+			}
+			if x < 0 || int64(x) >= limit {
+				return nil, ErrXOutOfRange
+			}
+			if _, ok := seen[x]; ok {
+				return nil, ErrDuplicateShare
+			}
+			seen[x] = nil
+		}
+
+		return o.xs, nil
+	}
+
+	xs := make([]GfElement, nshares)
+
+	if !o.randomXs {
+		for i := range xs {
+			xs[i] = GfElement(i + 1)
+		}
+		return xs, nil
+	}
+
+	if int64(nshares) >= randomLimit {
+		return nil, ErrTooManyRandomXs
+	}
+
+	// draw from 1, ..., randomLimit-1, rejecting repeats
+	nonzero := big.NewInt(randomLimit - 1)
+	seen := make(map[GfElement]any, nshares)
+	for i := 0; i < nshares; {
+		n, err := crand.Int(r, nonzero)
+		if err != nil {
+			return nil, err
+		}
+
+		x := GfElement(n.Int64() + 1)
+		if _, ok := seen[x]; ok {
+			continue
+		}
+		seen[x] = nil
+		xs[i] = x
+		i++
+	}
+
+	return xs, nil
+}
@@ -0,0 +1,99 @@
+package shamir
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestExplicitXs(t *testing.T) {
+	secret := []byte("employee ids")
+	s, err := NewShamirSecret(0x11d, 4, 3, secret, WithXs(101, 7, 250, 42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares := s.GetShares()
+
+	for i, want := range []string{"101", "7", "250", "42"} {
+		if have := shares[i].GetXString(); have != want {
+			t.Errorf("share %d is at x = %s, not %s", i, have, want)
+		}
+	}
+
+	recovered, err := RecoverSecret(shares[1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		t.Errorf("recovered %q, not %q", recovered, secret)
+	}
+
+	// shares over a prime field can be at x values beyond a byte
+	field, _ := PrimeFieldByName("p256")
+	s, err = NewShamirSecret(0, 3, 2, []byte{42}, WithPrimeField(field), WithXs(100000, 2, 70000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	recovered, err = RecoverSecret(s.GetShares()[:2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recovered[len(recovered)-1] != 42 {
+		t.Errorf("recovered %x", recovered)
+	}
+}
+
+func TestInvalidXs(t *testing.T) {
+	tests := []struct {
+		xs  []GfElement
+		err error
+	}{
+		{[]GfElement{1, 2}, ErrWrongNumberOfXs},
+		{[]GfElement{1, 2, 2}, ErrDuplicateShare},
+		{[]GfElement{0, 1, 2}, ErrZeroX},
+		{[]GfElement{1, 2, 256}, ErrXOutOfRange},
+		{[]GfElement{1, 2, -3}, ErrXOutOfRange},
+	}
+
+	for _, test := range tests {
+		if _, err := NewShamirSecret(0x11d, 3, 2, []byte("x"), WithXs(test.xs...)); err != test.err {
+			t.Errorf("x coordinates %v should fail with %v, got %v", test.xs, test.err, err)
+		}
+	}
+}
+
+func TestRandomXs(t *testing.T) {
+	secret := []byte("unpredictable")
+	s, err := NewShamirSecret(0x11d, 200, 3, secret, WithRandomXs())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sequential := true
+	for i, share := range s.GetShares() {
+		if share.x < 1 || share.x > 255 {
+			t.Errorf("random x = %d is not a nonzero element of GF(256)", share.x)
+		}
+		if share.x != GfElement(i+1) {
+			sequential = false
+		}
+	}
+	if sequential {
+		t.Error("random x coordinates were 1, ..., n")
+	}
+
+	recovered, err := RecoverSecret(s.GetShares()[50:53])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		t.Errorf("recovered %q, not %q", recovered, secret)
+	}
+
+	// all 255 nonzero elements can be used, but no more
+	if _, err := NewShamirSecret(0x11d, 255, 2, secret, WithRandomXs()); err != nil {
+		t.Errorf("255 random x coordinates should fit in GF(256), got %v", err)
+	}
+	if _, err := NewShamirSecret(0x11d, 256, 2, secret, WithRandomXs()); err != ErrTooManyRandomXs {
+		t.Errorf("256 random x coordinates shouldn't fit in GF(256), got %v", err)
+	}
+}
@@ -0,0 +1,271 @@
+package shamir
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// information dispersal splits data into n fragments of about 1/k its size, any k of which rebuild it
+// each block of k bytes is the coefficients of a polynomial of degree k-1, and the fragments are its values at x = 1, ..., n
+// plain dispersal (Rabin's IDA) gives availability without secrecy, since every fragment reveals something about the data
+// AONT-IDA first applies an all-or-nothing transform, so fewer than k fragments reveal nothing unless the cipher is broken
+// dispersed fragments look like shamirida-<id>-<poly>-<k>-<x>-<y> or shamiraont-<id>-<poly>-<k>-<x>-<y>
+
+type Scheme string
+
+const (
+	SchemeShamir Scheme = ""     // Shamir's secret sharing, where each share is as large as the secret
+	SchemeIda    Scheme = "ida"  // Rabin's information dispersal, which doesn't keep the data secret
+	SchemeAont   Scheme = "aont" // an all-or-nothing transform followed by information dispersal
+)
+
+var ErrUnknownScheme error = errors.New("scheme must be ida or aont")
+var ErrUnsupportedScheme error = errors.New("operation is only supported for shares of Shamir's scheme")
+var ErrMismatchedScheme error = errors.New("shares were made by different schemes")
+var ErrInvalidPackage error = errors.New("dispersed secret does not decrypt; a fragment is wrong, or there are fewer than the threshold")
+
+var dispersedRegexp = regexp.MustCompile(`shamir(ida|aont)-(\w+)-(\w+)-(\d+)-(\w+)-([\w\+\/]+)`)
+
+// WithScheme disperses the secret into fragments instead of sharing it with Shamir's scheme
+func WithScheme(scheme Scheme) Option {
+	return func(o *options) {
+		o.scheme = scheme
+	}
+}
+
+// ParseScheme parses the name of a dispersal scheme, as used in share labels
+func ParseScheme(name string) (Scheme, error) {
+	switch Scheme(name) {
+	case SchemeIda, SchemeAont:
+		return Scheme(name), nil
+	default:
+		return SchemeShamir, ErrUnknownScheme
+	}
+}
+
+// disperse the secret into fragments over GF(2^m), drawing the AONT's key from r
+func (shamir *Shamir) splitDispersed(field Gf2m, scheme Scheme, secret []byte, r io.Reader) error {
+	data := secret
+	if scheme == SchemeAont {
+		pkg, err := aontPackage(secret, r)
+		if err != nil {
+			return err
+		}
+		defer pkg.Close()
+		data = pkg.Bytes()
+	}
+
+	k := shamir.threshold
+	paddedBuf, err := padSecret(data, k)
+	if err != nil {
+		return err
+	}
+	defer paddedBuf.Close()
+	padded := paddedBuf.Bytes()
+	nblocks := len(padded) / k
+
+	for i := range shamir.shares {
+		shamir.shares[i].scheme = scheme
+		shamir.shares[i].y = make([]GfElement, nblocks)
+	}
+
+	p, pBuf, err := lockedElements(k)
+	if err != nil {
+		return err
+	}
+	defer pBuf.Close()
+
+	for b := range nblocks {
+		for j := range p {
+			p[j] = GfElement(padded[b*k+j])
+		}
+		for _, share := range shamir.shares {
+			share.y[b] = field.EvaluatePolynomial(p, share.x)
+		}
+	}
+
+	return nil
+}
+
+// recover the data dispersed in the fragments into memory provided by alloc
+func recoverDispersed(shares []Share, o options, alloc func(n int) ([]byte, error)) ([]byte, error) {
+	k := shares[0].threshold
+	if k < 1 {
+		return nil, ErrInvalidThreshold
+	}
+	if len(shares) < k {
+		return nil, ErrTooFewShares
+	}
+
+	// any k fragments determine the polynomials, so the rest are ignored
+	field := NewField(int(shares[0].primitivePoly))
+	xs := make([]GfElement, k)
+	for i := range xs {
+		xs[i] = shares[i].x
+	}
+
+	basis, err := lagrangeBasis(field, xs)
+	if err != nil {
+		return nil, err
+	}
+
+	paddedBuf, err := NewSecretBuffer(len(shares[0].y) * k)
+	if err != nil {
+		return nil, err
+	}
+	defer paddedBuf.Close()
+	padded := paddedBuf.Bytes()
+
+	for b := range shares[0].y {
+		for j := range k {
+			c := GfElement(0)
+			for i := range xs {
+				c = field.Add(c, field.Multiply(shares[i].y[b], basis[i].Coefficient(j)))
+			}
+			padded[b*k+j] = byte(c)
+		}
+	}
+
+	n, err := unpaddedLength(padded)
+	if err != nil {
+		return nil, err
+	}
+	data := padded[:n]
+
+	if shares[0].scheme == SchemeAont {
+		unpackaged, err := aontUnpackage(data)
+		if err != nil {
+			return nil, err
+		}
+		defer unpackaged.Close()
+		data = unpackaged.Bytes()
+	}
+
+	secret, err := alloc(len(data))
+	if err != nil {
+		return nil, err
+	}
+	copy(secret, data)
+
+	if o.commitment != "" {
+		if err := VerifyRecovered(secret, o.commitment); err != nil {
+			Wipe(secret)
+			return nil, err
+		}
+	}
+
+	return secret, nil
+}
+
+// the all-or-nothing transform of AONT-RS (Resch and Plank, 2011)
+// the secret is encrypted under a random key, and the key is appended masked by a hash of the whole ciphertext,
+// so the key can't be recovered without every byte of the package
+// the package reveals the secret to anyone holding all of it, so it is kept in a SecretBuffer
+func aontPackage(secret []byte, r io.Reader) (*SecretBuffer, error) {
+	key := make([]byte, chacha20poly1305.KeySize)
+	if _, err := io.ReadFull(r, key); err != nil {
+		return nil, err
+	}
+	defer Wipe(key)
+
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg, err := NewSecretBuffer(len(secret) + aead.Overhead() + len(key))
+	if err != nil {
+		return nil, err
+	}
+
+	// each key encrypts only one message, so a zero nonce is safe
+	nonce := make([]byte, aead.NonceSize())
+	ciphertext := aead.Seal(pkg.Bytes()[:0], nonce, secret, nil)
+
+	mask := sha256.Sum256(ciphertext)
+	subtle.XORBytes(pkg.Bytes()[len(ciphertext):], key, mask[:])
+
+	return pkg, nil
+}
+
+// invert aontPackage, decrypting into a SecretBuffer
+func aontUnpackage(pkg []byte) (*SecretBuffer, error) {
+	if len(pkg) < chacha20poly1305.KeySize+chacha20poly1305.Overhead {
+		return nil, ErrInvalidPackage
+	}
+
+	ciphertext := pkg[:len(pkg)-chacha20poly1305.KeySize]
+	mask := sha256.Sum256(ciphertext)
+	key := make([]byte, chacha20poly1305.KeySize)
+	subtle.XORBytes(key, pkg[len(ciphertext):], mask[:])
+	defer Wipe(key)
+
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := NewSecretBuffer(len(ciphertext) - aead.Overhead())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := aead.Open(secret.Bytes()[:0], make([]byte, aead.NonceSize()), ciphertext, nil); err != nil {
+		secret.Close()
+		return nil, ErrInvalidPackage
+	}
+
+	return secret, nil
+}
+
+// parse the dispersed fragments in input
+func parseDispersedShares(input string) ([]Share, error) {
+	shares := make([]Share, 0)
+
+	for _, match := range dispersedRegexp.FindAllStringSubmatch(input, -1) {
+		scheme, err := ParseScheme(match[1])
+		if err != nil {
+			return nil, err
+		}
+
+		primitivePoly, err := strconv.ParseInt(match[3], 16, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		threshold, err := strconv.Atoi(match[4])
+		if err != nil {
+			return nil, err
+		}
+
+		x, err := strconv.ParseInt(match[5], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		ydata, err := base64.RawStdEncoding.DecodeString(match[6])
+		if err != nil {
+			return nil, err
+		}
+
+		y := make([]GfElement, len(ydata))
+		for i := range ydata {
+			y[i] = GfElement(ydata[i])
+		}
+		Wipe(ydata)
+
+		share := NewShare(match[2], primitivePoly, GfElement(x), y)
+		share.scheme = scheme
+		share.threshold = threshold
+		shares = append(shares, share)
+	}
+
+	return shares, nil
+}
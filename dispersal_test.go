@@ -0,0 +1,122 @@
+package shamir
+
+import (
+	"bytes"
+	"crypto/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDisperse(t *testing.T) {
+	data := make([]byte, 1000)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, scheme := range []Scheme{SchemeIda, SchemeAont} {
+		s, err := NewShamirSecret(0x11d, 7, 4, data, WithScheme(scheme))
+		if err != nil {
+			t.Fatal(err)
+		}
+		shares := s.GetShares()
+
+		// fragments are about a quarter of the data, plus padding and the AONT's key and tag
+		if have := len(shares[0].y); have > len(data)/4+20 {
+			t.Errorf("%s fragments are %d bytes, too large for k=4", scheme, have)
+		}
+
+		for _, subset := range [][]Share{shares[:4], shares[3:], {shares[6], shares[1], shares[4], shares[2], shares[0]}} {
+			recovered, err := RecoverSecret(subset, WithCommitment(s.GetCommitment()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(recovered, data) {
+				t.Errorf("%s recovered the wrong data", scheme)
+			}
+		}
+
+		if _, err := RecoverSecret(shares[:3]); err != ErrTooFewShares {
+			t.Errorf("%s with fewer than k fragments should fail, got %v", scheme, err)
+		}
+	}
+}
+
+func TestDisperseShortData(t *testing.T) {
+	for n := 0; n < 6; n++ {
+		data := bytes.Repeat([]byte{0x80}, n)
+		s, err := NewShamirSecret(0x11b, 3, 3, data, WithScheme(SchemeIda))
+		if err != nil {
+			t.Fatal(err)
+		}
+		recovered, err := RecoverSecret(s.GetShares())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(recovered, data) {
+			t.Errorf("recovered %x, not %x", recovered, data)
+		}
+	}
+}
+
+func TestAontTamper(t *testing.T) {
+	s, err := NewShamirSecret(0x11d, 5, 3, []byte("all or nothing"), WithScheme(SchemeAont))
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares := s.GetShares()
+
+	bad := tamper(shares[1], 0)
+	if _, err := RecoverSecret([]Share{shares[0], bad, shares[2]}); err == nil {
+		t.Error("a tampered fragment should be detected")
+	}
+}
+
+func TestDispersedShareString(t *testing.T) {
+	s, err := NewShamirSecret(0x11d, 4, 2, []byte("labels"), WithScheme(SchemeAont))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	strs := make([]string, 0)
+	for _, share := range s.GetShares() {
+		if !strings.HasPrefix(share.String(), "shamiraont-"+s.GetId()+"-11d-2-") {
+			t.Errorf("unexpected label %s", share.String())
+		}
+		strs = append(strs, share.String())
+	}
+
+	parsed, err := NewSharesFromString(strings.Join(strs[2:], " "))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed) != 2 || parsed[0].GetScheme() != SchemeAont || parsed[0].GetThreshold() != 2 {
+		t.Fatalf("parsed %v", parsed)
+	}
+	recovered, err := RecoverSecret(parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(recovered) != "labels" {
+		t.Errorf("recovered %q", recovered)
+	}
+
+	// armored fragments keep their scheme
+	armored, err := NewSharesFromString(string(s.GetShares()[0].Armor("", time.Time{})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if armored[0].String() != strs[0] {
+		t.Errorf("armored fragment parsed as %s, not %s", armored[0], strs[0])
+	}
+
+	// fragments can't be mixed with shares of Shamir's scheme
+	ordinary := NewShare(s.GetId(), 0x11d, 9, make([]GfElement, len(parsed[0].y)))
+	if _, err := RecoverSecret(append(parsed, ordinary)); err != ErrMismatchedScheme {
+		t.Errorf("mixing schemes should fail, got %v", err)
+	}
+
+	if _, err := s.GetShares()[0].Wrap([]byte("pw")); err != ErrUnsupportedScheme {
+		t.Errorf("wrapping a fragment should fail, got %v", err)
+	}
+}
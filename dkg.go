@@ -0,0 +1,236 @@
+package shamir
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"filippo.io/edwards25519"
+)
+
+// dealerless distributed key generation (Pedersen's Joint-Feldman DKG) over edwards25519
+//
+// in the first round, each participant chooses a random polynomial, publishes commitments to its coefficients
+// along with a proof that it knows the constant term, and privately sends the polynomial's value at j to participant j.
+// each participant then checks what it received against the commitments and sums the values into its share,
+// so the jointly generated secret is the sum of the constant terms, which no single participant ever knows.
+
+var ErrInvalidSession error = errors.New("session ID must contain only letters, digits, and underscores")
+var ErrInvalidParticipant error = errors.New("participant index must be between 1 and the number of participants")
+var ErrMissingDkgMessage error = errors.New("missing a round 1 message from a participant")
+
+var sessionRegexp = regexp.MustCompile(`^\w+$`)
+
+// ValidateSession checks that a session or secret ID is safe to use in share strings and file names
+func ValidateSession(session string) error {
+	if !sessionRegexp.MatchString(session) {
+		return ErrInvalidSession
+	}
+	return nil
+}
+
+// published by each participant in round 1
+type DkgCommitment struct {
+	Session     string   `json:"session"`
+	From        int      `json:"from"`
+	Commitments [][]byte `json:"commitments"` // a_k*B for each coefficient a_k of the participant's polynomial
+	ProofR      []byte   `json:"proof_r"`     // Schnorr proof of knowledge of a_0
+	ProofZ      []byte   `json:"proof_z"`
+}
+
+// sent privately from one participant to another in round 1
+type DkgShare struct {
+	Session string `json:"session"`
+	From    int    `json:"from"`
+	To      int    `json:"to"`
+	Value   []byte `json:"value"` // f_From(To)
+}
+
+// a participant whose round 1 messages are inconsistent, and should be excluded from the next attempt
+type DkgComplaintError struct {
+	Participant int
+	Reason      string
+}
+
+func (err *DkgComplaintError) Error() string {
+	return fmt.Sprintf("participant %d misbehaved: %s", err.Participant, err.Reason)
+}
+
+type DkgParticipant struct {
+	session       string
+	index         int
+	nparticipants int
+	threshold     int
+}
+
+// NewDkgParticipant sets up participant index (from 1 to nparticipants) in a session where threshold of the participants can reconstruct the secret
+func NewDkgParticipant(session string, index, nparticipants, threshold int) (*DkgParticipant, error) {
+	if !sessionRegexp.MatchString(session) {
+		return nil, ErrInvalidSession
+	}
+	if threshold < 1 || threshold > nparticipants {
+		return nil, ErrThresholdTooLarge
+	}
+	if index < 1 || index > nparticipants {
+		return nil, ErrInvalidParticipant
+	}
+
+	return &DkgParticipant{session: session, index: index, nparticipants: nparticipants, threshold: threshold}, nil
+}
+
+func (p *DkgParticipant) GetIndex() int {
+	return p.index
+}
+
+// the challenge for the proof of knowledge, binding it to the session and participant so it can't be replayed
+func dkgChallenge(session string, from int, a0, r []byte) *edwards25519.Scalar {
+	return hashToScalar("shamir-dkg-pok", []byte(session), scalarFromIndex(from).Bytes(), a0, r)
+}
+
+// Round1 chooses the participant's random polynomial, returning the commitment to publish to everyone
+// and the shares to send privately, where shares[j-1] is for participant j (including this participant).
+// The polynomial itself is wiped, so Round1 must only be called once.
+func (p *DkgParticipant) Round1() (DkgCommitment, []DkgShare, error) {
+	coefficients := make([]*edwards25519.Scalar, p.threshold)
+	defer func() {
+		for _, a := range coefficients {
+			if a != nil {
+				a.Set(edwards25519.NewScalar())
+			}
+		}
+	}()
+
+	commitment := DkgCommitment{Session: p.session, From: p.index, Commitments: make([][]byte, p.threshold)}
+	for k := range coefficients {
+		var err error
+		coefficients[k], err = randomScalar()
+		if err != nil {
+			return DkgCommitment{}, nil, err
+		}
+		commitment.Commitments[k] = new(edwards25519.Point).ScalarBaseMult(coefficients[k]).Bytes()
+	}
+
+	// prove knowledge of a_0, so no participant can choose its commitment to cancel out the others
+	r, err := randomScalar()
+	if err != nil {
+		return DkgCommitment{}, nil, err
+	}
+	commitment.ProofR = new(edwards25519.Point).ScalarBaseMult(r).Bytes()
+	c := dkgChallenge(p.session, p.index, commitment.Commitments[0], commitment.ProofR)
+	commitment.ProofZ = edwards25519.NewScalar().MultiplyAdd(c, coefficients[0], r).Bytes()
+	r.Set(edwards25519.NewScalar())
+
+	shares := make([]DkgShare, p.nparticipants)
+	for j := range shares {
+		shares[j] = DkgShare{
+			Session: p.session,
+			From:    p.index,
+			To:      j + 1,
+			Value:   evaluateScalarPolynomial(coefficients, scalarFromIndex(j+1)).Bytes(),
+		}
+	}
+
+	return commitment, shares, nil
+}
+
+// check a participant's commitment, returning the committed points
+func (p *DkgParticipant) verifyCommitment(commitment DkgCommitment) ([]*edwards25519.Point, error) {
+	complain := func(reason string) error {
+		return &DkgComplaintError{Participant: commitment.From, Reason: reason}
+	}
+
+	if commitment.Session != p.session {
+		return nil, complain("commitment is for another session")
+	}
+	if len(commitment.Commitments) != p.threshold {
+		return nil, complain("commitment has the wrong threshold")
+	}
+
+	points := make([]*edwards25519.Point, p.threshold)
+	for k, b := range commitment.Commitments {
+		var err error
+		points[k], err = parsePoint(b)
+		if err != nil {
+			return nil, complain("commitment is not a valid point")
+		}
+	}
+
+	r, err := parsePoint(commitment.ProofR)
+	if err != nil {
+		return nil, complain("proof of knowledge is malformed")
+	}
+	z, err := edwards25519.NewScalar().SetCanonicalBytes(commitment.ProofZ)
+	if err != nil {
+		return nil, complain("proof of knowledge is malformed")
+	}
+
+	// z*B == R + c*A_0
+	c := dkgChallenge(p.session, commitment.From, commitment.Commitments[0], commitment.ProofR)
+	lhs := new(edwards25519.Point).ScalarBaseMult(z)
+	rhs := new(edwards25519.Point).ScalarMult(c, points[0])
+	rhs.Add(rhs, r)
+	if lhs.Equal(rhs) != 1 {
+		return nil, complain("proof of knowledge is invalid")
+	}
+
+	return points, nil
+}
+
+// Finalize checks the round 1 messages from every participant (including this one) and combines them into this participant's share of the joint secret.
+// It also returns the joint public key, which is the secret times the edwards25519 base point, encoded like an ed25519 public key.
+// If a participant's messages are inconsistent, a *DkgComplaintError identifies them.
+func (p *DkgParticipant) Finalize(commitments []DkgCommitment, shares []DkgShare) (Share, []byte, error) {
+	byParticipant := make(map[int]DkgCommitment, len(commitments))
+	for _, commitment := range commitments {
+		byParticipant[commitment.From] = commitment
+	}
+
+	received := make(map[int]DkgShare, len(shares))
+	for _, share := range shares {
+		if share.To == p.index {
+			received[share.From] = share
+		}
+	}
+
+	secret := edwards25519.NewScalar()
+	defer secret.Set(edwards25519.NewScalar())
+	publicKey := edwards25519.NewIdentityPoint()
+	x := scalarFromIndex(p.index)
+
+	for i := 1; i <= p.nparticipants; i++ {
+		commitment, ok := byParticipant[i]
+		if !ok {
+			return Share{}, nil, ErrMissingDkgMessage
+		}
+		share, ok := received[i]
+		if !ok {
+			return Share{}, nil, ErrMissingDkgMessage
+		}
+
+		points, err := p.verifyCommitment(commitment)
+		if err != nil {
+			return Share{}, nil, err
+		}
+
+		if share.Session != p.session {
+			return Share{}, nil, &DkgComplaintError{Participant: i, Reason: "share is for another session"}
+		}
+		value, err := edwards25519.NewScalar().SetCanonicalBytes(share.Value)
+		if err != nil {
+			return Share{}, nil, &DkgComplaintError{Participant: i, Reason: "share is not a valid scalar"}
+		}
+
+		// f_i(x)*B must match the commitments to f_i
+		if new(edwards25519.Point).ScalarBaseMult(value).Equal(evaluateCommitments(points, x)) != 1 {
+			return Share{}, nil, &DkgComplaintError{Participant: i, Reason: "share does not match the commitment"}
+		}
+
+		secret.Add(secret, value)
+		publicKey.Add(publicKey, points[0])
+		value.Set(edwards25519.NewScalar())
+	}
+
+	share := NewScalarShare(p.session, p.index, secret)
+	share.threshold = p.threshold
+	return share, publicKey.Bytes(), nil
+}
@@ -0,0 +1,155 @@
+package shamir
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"filippo.io/age"
+	"filippo.io/edwards25519"
+)
+
+// run every participant's round 1, returning the participants and their messages
+func runDkgRound1(t *testing.T, n, k int) ([]*DkgParticipant, []DkgCommitment, []DkgShare) {
+	participants := make([]*DkgParticipant, n)
+	commitments := make([]DkgCommitment, 0)
+	shares := make([]DkgShare, 0)
+
+	for i := range participants {
+		var err error
+		participants[i], err = NewDkgParticipant("TESTDKG", i+1, n, k)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		commitment, sent, err := participants[i].Round1()
+		if err != nil {
+			t.Fatal(err)
+		}
+		commitments = append(commitments, commitment)
+		shares = append(shares, sent...)
+	}
+
+	return participants, commitments, shares
+}
+
+func TestDkg(t *testing.T) {
+	participants, commitments, shares := runDkgRound1(t, 5, 3)
+
+	results := make([]Share, len(participants))
+	var publicKey []byte
+	for i, p := range participants {
+		share, pk, err := p.Finalize(commitments, shares)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if publicKey != nil && !bytes.Equal(pk, publicKey) {
+			t.Fatal("participants disagree on the public key")
+		}
+		publicKey = pk
+		results[i] = share
+	}
+
+	// the shares survive a round trip through their string form
+	parsed, err := NewSharesFromString(results[4].String() + " " + results[1].String() + " " + results[2].String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed[0].GetGroup() != GroupEd25519 || parsed[0].ShareLabel() != "shamir-TESTDKG-ed25519-5" {
+		t.Fatalf("unexpected share %s", parsed[0])
+	}
+
+	// any threshold of the shares reconstruct the secret behind the public key
+	secret, err := RecoverSecret(parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := edwards25519.NewScalar().SetCanonicalBytes(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(new(edwards25519.Point).ScalarBaseMult(s).Bytes(), publicKey) {
+		t.Fatal("reconstructed secret does not match the public key")
+	}
+
+	other, err := RecoverSecret(results[:3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(other, secret) {
+		t.Fatal("different subsets reconstruct different secrets")
+	}
+}
+
+func TestDkgShareEncryption(t *testing.T) {
+	_, _, shares := runDkgRound1(t, 2, 2)
+
+	recipient, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ciphertext, err := shares[1].Encrypt(recipient.Recipient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(ciphertext, []byte(`"value"`)) {
+		t.Fatal("encrypted share contains its plaintext")
+	}
+
+	share, err := DecryptDkgShare(ciphertext, recipient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if share.Session != shares[1].Session || share.From != shares[1].From || share.To != shares[1].To || !bytes.Equal(share.Value, shares[1].Value) {
+		t.Fatal("decrypted share does not match")
+	}
+
+	if _, err := DecryptDkgShare(ciphertext, other); err == nil {
+		t.Fatal("share decrypted with the wrong identity")
+	}
+}
+
+func TestDkgComplaints(t *testing.T) {
+	participants, commitments, shares := runDkgRound1(t, 3, 2)
+
+	// participant 2 sends participant 1 a bad share
+	for i := range shares {
+		if shares[i].From == 2 && shares[i].To == 1 {
+			shares[i].Value = scalarFromIndex(7).Bytes()
+		}
+	}
+
+	var complaint *DkgComplaintError
+	_, _, err := participants[0].Finalize(commitments, shares)
+	if !errors.As(err, &complaint) || complaint.Participant != 2 {
+		t.Fatalf("have %v, want a complaint against participant 2", err)
+	}
+
+	// participant 3 is unaffected
+	if _, _, err := participants[2].Finalize(commitments, shares); err != nil {
+		t.Fatal(err)
+	}
+
+	// participant 3 replaces its commitment to a_0 without knowing its discrete log
+	commitments[2].Commitments[0] = new(edwards25519.Point).ScalarBaseMult(scalarFromIndex(5)).Bytes()
+	_, _, err = participants[1].Finalize(commitments, shares)
+	if !errors.As(err, &complaint) || complaint.Participant != 3 {
+		t.Fatalf("have %v, want a complaint against participant 3", err)
+	}
+
+	if _, _, err := participants[1].Finalize(commitments[:2], shares); err != ErrMissingDkgMessage {
+		t.Fatalf("have %v, want %v", err, ErrMissingDkgMessage)
+	}
+
+	if _, err := NewDkgParticipant("bad-session", 1, 3, 2); err != ErrInvalidSession {
+		t.Errorf("have %v, want %v", err, ErrInvalidSession)
+	}
+	if _, err := NewDkgParticipant("S", 4, 3, 2); err != ErrInvalidParticipant {
+		t.Errorf("have %v, want %v", err, ErrInvalidParticipant)
+	}
+}
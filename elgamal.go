@@ -0,0 +1,198 @@
+package shamir
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io"
+
+	"filippo.io/edwards25519"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+)
+
+// threshold decryption with hashed ElGamal over edwards25519
+//
+// a message is encrypted to the public key Y = s*B with an ephemeral R = r*B, under a key derived from r*Y.
+// each holder of a share s_i of s computes the partial decryption s_i*R, and any k of them interpolate to s*R = r*Y,
+// so the combiner recovers the message without the private key s ever being reassembled.
+// ciphertexts are R || ChaCha20-Poly1305(message), with R as additional data.
+
+const elgamalInfo string = "shamir-elgamal-v1"
+
+var ErrInvalidCiphertext error = errors.New("invalid threshold ciphertext")
+var ErrPartialMismatch error = errors.New("partial decryption is for a different ciphertext")
+var ErrDecryptionFailed error = errors.New("decryption failed; a partial decryption is wrong, or there are fewer than the threshold")
+
+// produced by each holder, and passed to whoever combines them
+type PartialDecryption struct {
+	Index     int    `json:"index"`
+	Ephemeral []byte `json:"ephemeral"` // R from the ciphertext
+	Value     []byte `json:"value"`     // s_i*R
+}
+
+// GenerateThresholdKey makes a random private key split into n shares with threshold k, returning the shares and the public key
+func GenerateThresholdKey(secret_id string, n, k int) ([]Share, []byte, error) {
+	if !sessionRegexp.MatchString(secret_id) {
+		return nil, nil, ErrInvalidSession
+	}
+	if k < 1 || k > n {
+		return nil, nil, ErrThresholdTooLarge
+	}
+
+	secret, err := randomScalar()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer secret.Set(edwards25519.NewScalar())
+
+	shares, err := splitScalar(secret_id, secret, n, k)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return shares, new(edwards25519.Point).ScalarBaseMult(secret).Bytes(), nil
+}
+
+// reject points with a small-order component, so a malicious ciphertext can't learn anything about a share from its partial decryption
+func parsePrimeOrderPoint(b []byte) (*edwards25519.Point, error) {
+	p, err := parsePoint(b)
+	if err != nil {
+		return nil, err
+	}
+
+	// P is torsion-free exactly when (8P)/8 == P
+	inverse8 := edwards25519.NewScalar().Invert(scalarFromIndex(8))
+	q := new(edwards25519.Point).MultByCofactor(p)
+	if p.Equal(edwards25519.NewIdentityPoint()) == 1 || q.ScalarMult(inverse8, q).Equal(p) != 1 {
+		return nil, ErrInvalidPoint
+	}
+
+	return p, nil
+}
+
+// the key for the message, derived from the shared point r*Y
+func elgamalKey(ephemeral, shared []byte) ([]byte, error) {
+	key := make([]byte, chacha20poly1305.KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, shared, ephemeral, []byte(elgamalInfo)), key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// split a ciphertext into its ephemeral point and sealed message
+func parseThresholdCiphertext(ciphertext []byte) (*edwards25519.Point, []byte, error) {
+	if len(ciphertext) < 32+chacha20poly1305.Overhead {
+		return nil, nil, ErrInvalidCiphertext
+	}
+
+	ephemeral, err := parsePrimeOrderPoint(ciphertext[:32])
+	if err != nil {
+		return nil, nil, ErrInvalidCiphertext
+	}
+
+	return ephemeral, ciphertext[32:], nil
+}
+
+// EncryptToThresholdKey encrypts message to a public key made by GenerateThresholdKey or a distributed key generation
+func EncryptToThresholdKey(publicKey, message []byte) ([]byte, error) {
+	y, err := parsePrimeOrderPoint(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := randomScalar()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Set(edwards25519.NewScalar())
+
+	ephemeral := new(edwards25519.Point).ScalarBaseMult(r).Bytes()
+	key, err := elgamalKey(ephemeral, new(edwards25519.Point).ScalarMult(r, y).Bytes())
+	if err != nil {
+		return nil, err
+	}
+	defer Wipe(key)
+
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// every message has a fresh key, so a fixed nonce is safe
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	return aead.Seal(append([]byte(nil), ephemeral...), nonce, message, ephemeral), nil
+}
+
+// PartialDecrypt computes a holder's partial decryption of the ciphertext
+func PartialDecrypt(share Share, ciphertext []byte) (PartialDecryption, error) {
+	ephemeral, _, err := parseThresholdCiphertext(ciphertext)
+	if err != nil {
+		return PartialDecryption{}, err
+	}
+
+	secret, err := share.Scalar()
+	if err != nil {
+		return PartialDecryption{}, err
+	}
+	defer secret.Set(edwards25519.NewScalar())
+
+	return PartialDecryption{
+		Index:     int(share.x),
+		Ephemeral: ephemeral.Bytes(),
+		Value:     new(edwards25519.Point).ScalarMult(secret, ephemeral).Bytes(),
+	}, nil
+}
+
+// CombineDecryption decrypts the ciphertext from at least a threshold of partial decryptions
+func CombineDecryption(ciphertext []byte, partials []PartialDecryption) ([]byte, error) {
+	ephemeral, sealed, err := parseThresholdCiphertext(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	if len(partials) == 0 {
+		return nil, ErrNoShares
+	}
+
+	indices := make([]int, len(partials))
+	for i, partial := range partials {
+		if string(partial.Ephemeral) != string(ephemeral.Bytes()) {
+			return nil, ErrPartialMismatch
+		}
+		indices[i] = partial.Index
+	}
+
+	// interpolate s*R from the s_i*R
+	shared := edwards25519.NewIdentityPoint()
+	for i, partial := range partials {
+		d, err := parsePoint(partial.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		ell, err := lagrangeCoefficient(indices[i], indices)
+		if err != nil {
+			return nil, err
+		}
+
+		shared.Add(shared, d.ScalarMult(ell, d))
+	}
+
+	key, err := elgamalKey(ciphertext[:32], shared.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	defer Wipe(key)
+
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	message, err := aead.Open(nil, nonce, sealed, ciphertext[:32])
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+
+	return message, nil
+}
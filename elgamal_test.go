@@ -0,0 +1,111 @@
+package shamir
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func partialDecryptions(t *testing.T, shares []Share, ciphertext []byte) []PartialDecryption {
+	partials := make([]PartialDecryption, len(shares))
+	for i, share := range shares {
+		var err error
+		partials[i], err = PartialDecrypt(share, ciphertext)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return partials
+}
+
+func TestThresholdDecryption(t *testing.T) {
+	shares, publicKey, err := GenerateThresholdKey("BACKUP", 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message := []byte("the backup encryption key")
+	ciphertext, err := EncryptToThresholdKey(publicKey, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, holders := range [][]Share{{shares[0], shares[1], shares[2]}, {shares[4], shares[2], shares[0]}, shares} {
+		plaintext, err := CombineDecryption(ciphertext, partialDecryptions(t, holders, ciphertext))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(plaintext, message) {
+			t.Fatalf("have %q, want %q", plaintext, message)
+		}
+	}
+
+	// keys from a distributed key generation work too
+	participants, commitments, dkgShares := runDkgRound1(t, 3, 2)
+	results := make([]Share, len(participants))
+	for i, p := range participants {
+		results[i], publicKey, err = p.Finalize(commitments, dkgShares)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ciphertext, err = EncryptToThresholdKey(publicKey, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext, err := CombineDecryption(ciphertext, partialDecryptions(t, results[1:], ciphertext))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(plaintext, message) {
+		t.Fatalf("have %q, want %q", plaintext, message)
+	}
+}
+
+func TestThresholdDecryptionErrors(t *testing.T) {
+	shares, publicKey, err := GenerateThresholdKey("BACKUP", 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ciphertext, err := EncryptToThresholdKey(publicKey, []byte("message"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := EncryptToThresholdKey(publicKey, []byte("message"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	partials := partialDecryptions(t, shares, ciphertext)
+	if _, err := CombineDecryption(ciphertext, partials[:1]); err != ErrDecryptionFailed {
+		t.Errorf("have %v, want %v", err, ErrDecryptionFailed)
+	}
+	if _, err := CombineDecryption(other, partials); err != ErrPartialMismatch {
+		t.Errorf("have %v, want %v", err, ErrPartialMismatch)
+	}
+
+	partials[1].Value = partials[2].Value
+	if _, err := CombineDecryption(ciphertext, partials[:2]); err != ErrDecryptionFailed {
+		t.Errorf("have %v, want %v", err, ErrDecryptionFailed)
+	}
+
+	// an ephemeral point with a small-order component is rejected
+	order2, _ := hex.DecodeString("ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f")
+	torsion, err := parsePoint(order2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ephemeral, err := parsePoint(ciphertext[:32])
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := append(ephemeral.Add(ephemeral, torsion).Bytes(), ciphertext[32:]...)
+	if _, err := PartialDecrypt(shares[0], tampered); err != ErrInvalidCiphertext {
+		t.Errorf("have %v, want %v", err, ErrInvalidCiphertext)
+	}
+	if _, err := PartialDecrypt(shares[0], ciphertext[:40]); err != ErrInvalidCiphertext {
+		t.Errorf("have %v, want %v", err, ErrInvalidCiphertext)
+	}
+}
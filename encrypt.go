@@ -0,0 +1,146 @@
+package shamir
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+	"filippo.io/age/agessh"
+	"filippo.io/age/armor"
+)
+
+// shares can be encrypted to their holders' age X25519 or SSH ed25519 public keys,
+// so that whoever distributes a secret never sees the shares in the clear
+
+var ErrInvalidRecipient error = errors.New("recipient must be an age X25519 public key (age1...) or an SSH ed25519 public key")
+var ErrInvalidIdentity error = errors.New("identity must contain age X25519 secret keys or an unencrypted SSH ed25519 private key")
+
+var encryptedRegexp = regexp.MustCompile(`(?s)` + armor.Header + `.*?` + armor.Footer)
+
+// ParseRecipient parses an age X25519 public key or an SSH ed25519 public key in authorized_keys format
+func ParseRecipient(s string) (age.Recipient, error) {
+	s = strings.TrimSpace(s)
+
+	if strings.HasPrefix(s, "age1") {
+		return age.ParseX25519Recipient(s)
+	}
+
+	if strings.HasPrefix(s, "ssh-ed25519 ") {
+		return agessh.ParseRecipient(s)
+	}
+
+	return nil, ErrInvalidRecipient
+}
+
+// ParseIdentities reads an age identity file or an SSH ed25519 private key
+func ParseIdentities(data []byte) ([]age.Identity, error) {
+	if identities, err := age.ParseIdentities(bytes.NewReader(data)); err == nil {
+		return identities, nil
+	}
+
+	identity, err := agessh.ParseIdentity(data)
+	if err != nil {
+		return nil, ErrInvalidIdentity
+	}
+
+	return []age.Identity{identity}, nil
+}
+
+// encrypt data to the recipients, returning ASCII-armored age ciphertext
+func encryptArmored(data []byte, recipients ...age.Recipient) ([]byte, error) {
+	var buf bytes.Buffer
+
+	a := armor.NewWriter(&buf)
+	w, err := age.Encrypt(a, recipients...)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	if err := a.Close(); err != nil {
+		return nil, err
+	}
+
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// Encrypt encrypts the share to the recipients, returning ASCII-armored age ciphertext
+func (share Share) Encrypt(recipients ...age.Recipient) ([]byte, error) {
+	return encryptArmored([]byte(share.String()), recipients...)
+}
+
+// EncryptArmor encrypts the PEM-armored form of the share, keeping its descriptive headers private as well
+func (share Share) EncryptArmor(recipients []age.Recipient, holder string, created time.Time) ([]byte, error) {
+	return encryptArmored(share.Armor(holder, created), recipients...)
+}
+
+// Encrypt encrypts the DKG share to its recipient, so it can be delivered through a shared directory
+func (share DkgShare) Encrypt(recipient age.Recipient) ([]byte, error) {
+	data, err := json.Marshal(share)
+	if err != nil {
+		return nil, err
+	}
+	defer Wipe(data)
+
+	return encryptArmored(data, recipient)
+}
+
+// DecryptDkgShare decrypts a DKG share encrypted with DkgShare.Encrypt
+func DecryptDkgShare(input []byte, identities ...age.Identity) (DkgShare, error) {
+	r, err := age.Decrypt(armor.NewReader(bytes.NewReader(input)), identities...)
+	if err != nil {
+		return DkgShare{}, err
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return DkgShare{}, err
+	}
+	defer Wipe(data)
+
+	var share DkgShare
+	if err := json.Unmarshal(data, &share); err != nil {
+		return DkgShare{}, err
+	}
+	return share, nil
+}
+
+// IsEncrypted reports whether the input contains age-encrypted shares
+func IsEncrypted(input string) bool {
+	return encryptedRegexp.MatchString(input)
+}
+
+// DecryptShares decrypts each age-encrypted block in the input and parses the shares inside, along with any unencrypted shares
+func DecryptShares(input string, identities ...age.Identity) ([]Share, error) {
+	var plaintext strings.Builder
+
+	for _, block := range encryptedRegexp.FindAllString(input, -1) {
+		r, err := age.Decrypt(armor.NewReader(strings.NewReader(block)), identities...)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+
+		plaintext.Write(data)
+		plaintext.WriteByte('\n')
+	}
+
+	plaintext.WriteString(encryptedRegexp.ReplaceAllString(input, ""))
+
+	return NewSharesFromString(plaintext.String())
+}
@@ -0,0 +1,111 @@
+package shamir
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"filippo.io/age"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestEncrypt(t *testing.T) {
+	secret := []byte("encrypted to each holder")
+
+	ageIdentity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshPriv, err := ssh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ageRecipient, err := ParseRecipient(ageIdentity.Recipient().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshRecipient, err := ParseRecipient(string(ssh.MarshalAuthorizedKey(sshPub)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ageIdentities, err := ParseIdentities([]byte(ageIdentity.String() + "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshIdentities, err := ParseIdentities(pem.EncodeToMemory(sshPriv))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewShamirSecret(0x11d, 3, 2, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares := s.GetShares()
+
+	first, err := shares[0].Encrypt(ageRecipient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := shares[1].EncryptArmor([]age.Recipient{sshRecipient}, "Bob", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !IsEncrypted(string(first)) || IsEncrypted(shares[0].String()) {
+		t.Fatal("IsEncrypted did not detect the encrypted share")
+	}
+	if bytes.Contains(first, []byte(shares[0].String())) {
+		t.Fatal("share is visible in the ciphertext")
+	}
+
+	// each holder can only decrypt their own share
+	if _, err := DecryptShares(string(first), sshIdentities...); err == nil {
+		t.Fatal("decrypted a share with the wrong identity")
+	}
+
+	recovered, err := DecryptShares(string(first), ageIdentities...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	more, err := DecryptShares(string(second), sshIdentities...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recovered = append(recovered, more...)
+
+	if len(recovered) != 2 || recovered[1].GetThreshold() != 2 {
+		t.Fatalf("recovered %d shares", len(recovered))
+	}
+
+	have, err := RecoverSecret(recovered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(have, secret) {
+		t.Fatalf("have %q, want %q", have, secret)
+	}
+}
+
+func TestEncryptErrors(t *testing.T) {
+	if _, err := ParseRecipient("ssh-rsa AAAA"); err != ErrInvalidRecipient {
+		t.Errorf("have %v, want %v", err, ErrInvalidRecipient)
+	}
+	if _, err := ParseIdentities([]byte("not a key")); err != ErrInvalidIdentity {
+		t.Errorf("have %v, want %v", err, ErrInvalidIdentity)
+	}
+}
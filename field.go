@@ -0,0 +1,66 @@
+package shamir
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// a finite field with elements of type E, over which secrets can be shared
+// implemented by Gf2m, Gf2mBig, and Fp
+type Field[E any] interface {
+	fmt.Stringer
+	Add(a, b E) E
+	Subtract(a, b E) E
+	Multiply(a, b E) E
+	Divide(a, b E) (E, error)
+	Element(i int64) E                 // the element represented by the integer i, used for constants and x coordinates
+	RandomFrom(r io.Reader) (E, error) // a uniformly random element drawn from r
+}
+
+// evaluate the polynomial with coefficients p (constant term first) at x
+func evaluatePolynomial[E any](field Field[E], p []E, x E) E {
+	y := field.Element(0)
+	for d := len(p) - 1; d > -1; d-- {
+		y = field.Add(field.Multiply(y, x), p[d])
+	}
+	return y
+}
+
+// choose a random polynomial of the given degree with the given constant term, drawing coefficients from r
+func randomPolynomial[E any](field Field[E], constant E, degree int, r io.Reader) ([]E, error) {
+	p := make([]E, degree+1)
+	p[0] = constant
+	for i := 1; i < len(p); i++ {
+		var err error
+		p[i], err = field.RandomFrom(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// evaluate the polynomial through the points (xs[j], ys[j]) at x using Lagrange interpolation
+func lagrangeInterpolate[E any](field Field[E], xs, ys []E, x E) (E, error) {
+	L := field.Element(0)
+	for j := range xs {
+		ell := field.Element(1)
+		for k := range xs {
+			if k == j {
+				continue
+			}
+			term, err := field.Divide(field.Subtract(x, xs[k]), field.Subtract(xs[j], xs[k]))
+			if err != nil {
+				return L, err
+			}
+			ell = field.Multiply(ell, term)
+		}
+		L = field.Add(L, field.Multiply(ys[j], ell))
+	}
+	return L, nil
+}
+
+var _ Field[GfElement] = Gf2m{}
+var _ Field[*big.Int] = Gf2mBig{}
+var _ Field[*big.Int] = Fp{}
@@ -0,0 +1,306 @@
+package shamir
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"sort"
+
+	"filippo.io/edwards25519"
+)
+
+// two-round threshold signing with shares of an ed25519 key, following FROST(Ed25519, SHA-512) from RFC 9591
+//
+// in round 1, each signer commits to a pair of random nonces.
+// in round 2, each signer combines its nonces, its key share, and everyone's commitments into a signature share.
+// the aggregated signature is an ordinary ed25519 signature, and the private key is never reconstructed.
+
+const frostContext string = "FROST-ED25519-SHA512-v1"
+
+var ErrNonceMismatch error = errors.New("signer's own commitment is missing or does not match its nonces")
+var ErrTooFewSigners error = errors.New("fewer signers than the threshold")
+var ErrSignatureShareMismatch error = errors.New("signature shares do not match the commitments")
+var ErrAggregateSignature error = errors.New("aggregated signature does not verify; a signature share or the public key is wrong")
+var ErrIdentityCommitment error = errors.New("signing commitment is the identity point")
+
+// published by each signer in round 1
+type SigningCommitment struct {
+	Index   int    `json:"index"`
+	Hiding  []byte `json:"hiding"`
+	Binding []byte `json:"binding"`
+}
+
+// published by each signer in round 2
+type SignatureShare struct {
+	Index int    `json:"index"`
+	Value []byte `json:"value"`
+}
+
+// the secret nonces a signer keeps between rounds, which must never be used twice
+type SigningNonces struct {
+	index   int
+	hiding  *edwards25519.Scalar
+	binding *edwards25519.Scalar
+}
+
+// SHA-512 of the parts, prefixed with the context string and tag unless the tag is empty
+func frostDigest(tag string, parts ...[]byte) []byte {
+	h := sha512.New()
+	if tag != "" {
+		h.Write([]byte(frostContext + tag))
+	}
+	for _, part := range parts {
+		h.Write(part)
+	}
+	return h.Sum(nil)
+}
+
+func frostHash(tag string, parts ...[]byte) *edwards25519.Scalar {
+	s, err := edwards25519.NewScalar().SetUniformBytes(frostDigest(tag, parts...))
+	if err != nil {
+		panic(err) // unreachable, since SHA-512 digests are 64 bytes
+	}
+	return s
+}
+
+// a nonce derived from fresh randomness and the key share, so a weak random number generator alone doesn't leak the key
+func generateNonce(secret *edwards25519.Scalar) (*edwards25519.Scalar, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	defer Wipe(b)
+	return frostHash("nonce", b, secret.Bytes()), nil
+}
+
+// SplitSigningKey splits an ed25519 private key into n scalar shares, any k of which can sign for its public key with SignRound1 and SignRound2
+func SplitSigningKey(secret_id string, key ed25519.PrivateKey, n, k int) ([]Share, error) {
+	if err := ValidateSession(secret_id); err != nil {
+		return nil, err
+	}
+	if k < 1 || k > n {
+		return nil, ErrThresholdTooLarge
+	}
+
+	// the scalar an ed25519 private key signs with
+	h := sha512.Sum512(key.Seed())
+	defer Wipe(h[:])
+	secret, err := edwards25519.NewScalar().SetBytesWithClamping(h[:32])
+	if err != nil {
+		return nil, err
+	}
+	defer secret.Set(edwards25519.NewScalar())
+
+	return splitScalar(secret_id, secret, n, k)
+}
+
+// SignRound1 generates a signer's nonces and the commitment to publish to the other signers
+func SignRound1(share Share) (*SigningNonces, SigningCommitment, error) {
+	secret, err := share.Scalar()
+	if err != nil {
+		return nil, SigningCommitment{}, err
+	}
+	defer secret.Set(edwards25519.NewScalar())
+
+	nonces := &SigningNonces{index: int(share.x)}
+	if nonces.hiding, err = generateNonce(secret); err != nil {
+		return nil, SigningCommitment{}, err
+	}
+	if nonces.binding, err = generateNonce(secret); err != nil {
+		return nil, SigningCommitment{}, err
+	}
+
+	return nonces, nonces.commitment(), nil
+}
+
+func (nonces *SigningNonces) commitment() SigningCommitment {
+	return SigningCommitment{
+		Index:   nonces.index,
+		Hiding:  new(edwards25519.Point).ScalarBaseMult(nonces.hiding).Bytes(),
+		Binding: new(edwards25519.Point).ScalarBaseMult(nonces.binding).Bytes(),
+	}
+}
+
+// Bytes serializes the nonces so they can be kept between rounds
+func (nonces *SigningNonces) Bytes() []byte {
+	b := binary.BigEndian.AppendUint32(nil, uint32(nonces.index))
+	b = append(b, nonces.hiding.Bytes()...)
+	return append(b, nonces.binding.Bytes()...)
+}
+
+// ParseSigningNonces parses nonces serialized with Bytes
+func ParseSigningNonces(b []byte) (*SigningNonces, error) {
+	if len(b) != 68 {
+		return nil, ErrInvalidScalar
+	}
+
+	var err error
+	nonces := &SigningNonces{index: int(binary.BigEndian.Uint32(b[:4]))}
+	if nonces.hiding, err = edwards25519.NewScalar().SetCanonicalBytes(b[4:36]); err != nil {
+		return nil, ErrInvalidScalar
+	}
+	if nonces.binding, err = edwards25519.NewScalar().SetCanonicalBytes(b[36:]); err != nil {
+		return nil, ErrInvalidScalar
+	}
+
+	return nonces, nil
+}
+
+// Wipe overwrites the nonces, which should be done as soon as they've been used
+func (nonces *SigningNonces) Wipe() {
+	nonces.hiding.Set(edwards25519.NewScalar())
+	nonces.binding.Set(edwards25519.NewScalar())
+}
+
+// the commitments of a signing session, sorted by index and checked for duplicates
+type signingSession struct {
+	commitments    []SigningCommitment
+	indices        []int
+	bindingFactors map[int]*edwards25519.Scalar
+	groupNonce     *edwards25519.Point
+	challenge      *edwards25519.Scalar
+}
+
+func newSigningSession(publicKey, message []byte, commitments []SigningCommitment) (*signingSession, error) {
+	if _, err := parsePoint(publicKey); err != nil {
+		return nil, err
+	}
+
+	s := &signingSession{commitments: append([]SigningCommitment(nil), commitments...)}
+	sort.Slice(s.commitments, func(i, j int) bool { return s.commitments[i].Index < s.commitments[j].Index })
+
+	encoded := make([]byte, 0, 96*len(s.commitments))
+	hiding := make([]*edwards25519.Point, len(s.commitments))
+	binding := make([]*edwards25519.Point, len(s.commitments))
+	for i, c := range s.commitments {
+		if i > 0 && c.Index == s.commitments[i-1].Index {
+			return nil, ErrDuplicateShare
+		}
+		if c.Index < 1 {
+			return nil, ErrInvalidParticipant
+		}
+
+		var err error
+		if hiding[i], err = parsePoint(c.Hiding); err != nil {
+			return nil, err
+		}
+		if binding[i], err = parsePoint(c.Binding); err != nil {
+			return nil, err
+		}
+
+		// an identity commitment would cancel a nonce out of the group commitment
+		identity := edwards25519.NewIdentityPoint()
+		if hiding[i].Equal(identity) == 1 || binding[i].Equal(identity) == 1 {
+			return nil, ErrIdentityCommitment
+		}
+
+		s.indices = append(s.indices, c.Index)
+		encoded = append(encoded, scalarFromIndex(c.Index).Bytes()...)
+		encoded = append(encoded, c.Hiding...)
+		encoded = append(encoded, c.Binding...)
+	}
+
+	// each signer's binding factor ties its nonces to the message and to every other signer's commitment
+	prefix := append([]byte(nil), publicKey...)
+	prefix = append(prefix, frostDigest("msg", message)...)
+	prefix = append(prefix, frostDigest("com", encoded)...)
+
+	s.bindingFactors = make(map[int]*edwards25519.Scalar, len(s.commitments))
+	s.groupNonce = edwards25519.NewIdentityPoint()
+	for i, c := range s.commitments {
+		rho := frostHash("rho", prefix, scalarFromIndex(c.Index).Bytes())
+		s.bindingFactors[c.Index] = rho
+		s.groupNonce.Add(s.groupNonce, hiding[i])
+		s.groupNonce.Add(s.groupNonce, new(edwards25519.Point).ScalarMult(rho, binding[i]))
+	}
+
+	// the same challenge as ed25519, so the result verifies as an ordinary signature
+	s.challenge = frostHash("", s.groupNonce.Bytes(), publicKey, message)
+
+	return s, nil
+}
+
+// SignRound2 computes a signer's share of the signature on message, given every signer's round 1 commitment.
+// The nonces are wiped, so they can't be reused even if signing fails.
+func SignRound2(share Share, nonces *SigningNonces, publicKey, message []byte, commitments []SigningCommitment) (SignatureShare, error) {
+	defer nonces.Wipe()
+
+	secret, err := share.Scalar()
+	if err != nil {
+		return SignatureShare{}, err
+	}
+	defer secret.Set(edwards25519.NewScalar())
+
+	index := int(share.x)
+	if nonces.index != index {
+		return SignatureShare{}, ErrNonceMismatch
+	}
+
+	s, err := newSigningSession(publicKey, message, commitments)
+	if err != nil {
+		return SignatureShare{}, err
+	}
+	if share.threshold > 0 && len(s.commitments) < share.threshold {
+		return SignatureShare{}, ErrTooFewSigners
+	}
+
+	own := nonces.commitment()
+	found := false
+	for _, c := range s.commitments {
+		if c.Index == index {
+			found = string(c.Hiding) == string(own.Hiding) && string(c.Binding) == string(own.Binding)
+		}
+	}
+	if !found {
+		return SignatureShare{}, ErrNonceMismatch
+	}
+
+	lambda, err := lagrangeCoefficient(index, s.indices)
+	if err != nil {
+		return SignatureShare{}, err
+	}
+
+	// z = d + e*rho + lambda*s*c
+	z := edwards25519.NewScalar().Multiply(lambda, secret)
+	z.Multiply(z, s.challenge)
+	z.MultiplyAdd(nonces.binding, s.bindingFactors[index], z)
+	z.Add(z, nonces.hiding)
+
+	return SignatureShare{Index: index, Value: z.Bytes()}, nil
+}
+
+// AggregateSignature combines the signature shares into an ed25519 signature on message, which is checked against publicKey
+func AggregateSignature(publicKey, message []byte, commitments []SigningCommitment, shares []SignatureShare) ([]byte, error) {
+	s, err := newSigningSession(publicKey, message, commitments)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(shares) != len(s.commitments) {
+		return nil, ErrSignatureShareMismatch
+	}
+
+	z := edwards25519.NewScalar()
+	seen := make(map[int]bool, len(shares))
+	for _, share := range shares {
+		if _, ok := s.bindingFactors[share.Index]; !ok || seen[share.Index] {
+			return nil, ErrSignatureShareMismatch
+		}
+		seen[share.Index] = true
+
+		zi, err := edwards25519.NewScalar().SetCanonicalBytes(share.Value)
+		if err != nil {
+			return nil, ErrInvalidScalar
+		}
+		z.Add(z, zi)
+	}
+
+	signature := append(s.groupNonce.Bytes(), z.Bytes()...)
+	if !ed25519.Verify(publicKey, message, signature) {
+		return nil, ErrAggregateSignature
+	}
+
+	return signature, nil
+}
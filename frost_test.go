@@ -0,0 +1,149 @@
+package shamir
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"filippo.io/edwards25519"
+)
+
+// run both signing rounds with the given shares
+func thresholdSign(t *testing.T, shares []Share, publicKey, message []byte) ([]SigningCommitment, []SignatureShare) {
+	nonces := make([]*SigningNonces, len(shares))
+	commitments := make([]SigningCommitment, len(shares))
+	for i, share := range shares {
+		var err error
+		nonces[i], commitments[i], err = SignRound1(share)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// signers keep their nonces on disk between rounds
+		nonces[i], err = ParseSigningNonces(nonces[i].Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	signatureShares := make([]SignatureShare, len(shares))
+	for i, share := range shares {
+		var err error
+		signatureShares[i], err = SignRound2(share, nonces[i], publicKey, message, commitments)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return commitments, signatureShares
+}
+
+func TestThresholdSignature(t *testing.T) {
+	publicKey, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shares, err := SplitSigningKey("SIGNING", key, 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message := []byte("release v1.2.3")
+	for _, signers := range [][]Share{{shares[0], shares[1], shares[2]}, {shares[4], shares[1], shares[3]}, shares} {
+		commitments, signatureShares := thresholdSign(t, signers, publicKey, message)
+
+		signature, err := AggregateSignature(publicKey, message, commitments, signatureShares)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ed25519.Verify(publicKey, message, signature) {
+			t.Fatal("threshold signature does not verify")
+		}
+	}
+
+	// shares from a distributed key generation sign for the joint public key
+	participants, dkgCommitments, dkgShares := runDkgRound1(t, 3, 2)
+	dkgResults := make([]Share, len(participants))
+	var jointKey []byte
+	for i, p := range participants {
+		dkgResults[i], jointKey, err = p.Finalize(dkgCommitments, dkgShares)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	commitments, signatureShares := thresholdSign(t, dkgResults[1:], jointKey, message)
+	signature, err := AggregateSignature(jointKey, message, commitments, signatureShares)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ed25519.Verify(jointKey, message, signature) {
+		t.Fatal("threshold signature does not verify")
+	}
+}
+
+func TestThresholdSignatureErrors(t *testing.T) {
+	publicKey, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shares, err := SplitSigningKey("SIGNING", key, 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := []byte("message")
+
+	// a signature share for a different message is caught when aggregating
+	commitments, signatureShares := thresholdSign(t, shares[:2], publicKey, message)
+	if _, err := AggregateSignature(publicKey, []byte("other"), commitments, signatureShares); err != ErrAggregateSignature {
+		t.Errorf("have %v, want %v", err, ErrAggregateSignature)
+	}
+	if _, err := AggregateSignature(publicKey, message, commitments, signatureShares[:1]); err != ErrSignatureShareMismatch {
+		t.Errorf("have %v, want %v", err, ErrSignatureShareMismatch)
+	}
+
+	// nonces are wiped after use
+	nonces, commitment, err := SignRound1(shares[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, other, err := SignRound1(shares[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := []SigningCommitment{commitment, other}
+	if _, err := SignRound2(shares[0], nonces, publicKey, message, all); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SignRound2(shares[0], nonces, publicKey, message, all); err != ErrNonceMismatch {
+		t.Errorf("have %v, want %v", err, ErrNonceMismatch)
+	}
+
+	nonces, commitment, err = SignRound1(shares[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SignRound2(shares[0], nonces, publicKey, message, []SigningCommitment{commitment}); err != ErrTooFewSigners {
+		t.Errorf("have %v, want %v", err, ErrTooFewSigners)
+	}
+
+	// an identity commitment is rejected
+	nonces, commitment, err = SignRound1(shares[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	identity := SigningCommitment{Index: 2, Hiding: edwards25519.NewIdentityPoint().Bytes(), Binding: other.Binding}
+	if _, err := SignRound2(shares[0], nonces, publicKey, message, []SigningCommitment{commitment, identity}); err != ErrIdentityCommitment {
+		t.Errorf("have %v, want %v", err, ErrIdentityCommitment)
+	}
+	identity = SigningCommitment{Index: 2, Hiding: other.Hiding, Binding: edwards25519.NewIdentityPoint().Bytes()}
+	if _, err := AggregateSignature(publicKey, message, []SigningCommitment{commitment, identity}, nil); err != ErrIdentityCommitment {
+		t.Errorf("have %v, want %v", err, ErrIdentityCommitment)
+	}
+
+	if _, err := SplitSigningKey("SIGNING", key, 2, 3); err != ErrThresholdTooLarge {
+		t.Errorf("have %v, want %v", err, ErrThresholdTooLarge)
+	}
+}
@@ -1,11 +1,23 @@
 package shamir
 
 import (
+	crand "crypto/rand"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
 	"math"
+	"strconv"
+	"strings"
+	"sync"
 )
 
+var ErrUnknownField error = errors.New("unknown field; use aes, rs, or a polynomial in hex")
+var ErrFieldTooLarge error = errors.New("fields larger than GF(2^24) are not supported")
+
+// the tables of larger fields would take too much memory
+const maxFieldDegree int = 24
+
 type GfElement int
 type GfPower int
 
@@ -15,16 +27,150 @@ type Gf2m struct {
 	primitivePoly int
 	logTable      []GfPower
 	antilogTable  []GfElement
+	generator     GfElement // the element whose powers fill the tables
 }
 
 func (field Gf2m) String() string {
-	return fmt.Sprintf("GF(2^%d) using primitive polynomial 0x%x", field.m, field.primitivePoly)
+	return fmt.Sprintf("GF(2^%d) using irreducible polynomial 0x%x", field.m, field.primitivePoly)
 }
 
 func (field Gf2m) GetNelements() int {
 	return field.n_elements
 }
 
+func (field Gf2m) GetPrimitivePoly() int {
+	return field.primitivePoly
+}
+
+// common fields by name, so callers don't need to know their polynomials
+var namedFields = map[string]int{
+	"aes": 0x11b, // x^8 + x^4 + x^3 + x + 1, used by AES and HashiCorp Vault
+	"rs":  0x11d, // x^8 + x^4 + x^3 + x^2 + 1, used by Reed-Solomon codes and the default for shares
+}
+
+// FieldPolynomialByName returns the polynomial of a named field such as aes, or parses a polynomial in hex such as 11d or 0x11d
+func FieldPolynomialByName(name string) (int, error) {
+	if poly, ok := namedFields[name]; ok {
+		return poly, nil
+	}
+
+	poly, err := strconv.ParseInt(strings.TrimPrefix(name, "0x"), 16, 32)
+	if err != nil || poly < 0b11 {
+		return 0, ErrUnknownField
+	}
+	if ComputeDegree(int(poly)) > maxFieldDegree {
+		return 0, ErrFieldTooLarge
+	}
+	if poly&0b1 != 1 {
+		return 0, ErrNonPrimitivePolynomial
+	}
+	if !IsIrreducible(int(poly)) {
+		return 0, ErrReduciblePolynomial
+	}
+	return int(poly), nil
+}
+
+// check a polynomial parsed from a share before building its field, since shares store one byte per y value
+func checkSharePolynomial(poly int64) error {
+	if poly < 0 || ComputeDegree(int(poly)) != 8 {
+		return ErrUnsupportedField
+	}
+	if !IsIrreducible(int(poly)) {
+		return ErrReduciblePolynomial
+	}
+	return nil
+}
+
+// FieldByName constructs a named field such as aes, or the field of a polynomial in hex
+func FieldByName(name string) (Gf2m, error) {
+	poly, err := FieldPolynomialByName(name)
+	if err != nil {
+		return Gf2m{}, err
+	}
+	return NewField(poly), nil
+}
+
+// IsIrreducible reports whether the polynomial has no nontrivial factors over GF(2), so that it defines GF(2^m)
+// by Ben-Or's test, a polynomial of degree m is irreducible if it shares no factor with x^(2^i) - x for i <= m/2
+func IsIrreducible(poly int) bool {
+	m := ComputeDegree(poly)
+	if m < 1 {
+		return false
+	}
+
+	r := GfElement(0b10)
+	for i := 1; i <= m/2; i++ {
+		r = multiplyModulo(r, r, poly, m)
+		if gcdGf2(poly, int(r^0b10)) != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// the greatest common divisor of two polynomials over GF(2), whose bits are their coefficients
+func gcdGf2(a, b int) int {
+	for b != 0 {
+		db := ComputeDegree(b)
+		for a != 0 && ComputeDegree(a) >= db {
+			a ^= b << (ComputeDegree(a) - db)
+		}
+		a, b = b, a
+	}
+	return a
+}
+
+// the smallest generator of the multiplicative group of the field, or 0 if the polynomial is reducible
+// x is a generator when the polynomial is primitive, but not for every irreducible polynomial, such as the AES polynomial 0x11b
+func findGenerator(poly int, m int) GfElement {
+	order := 1<<m - 1
+
+	// the prime factors of the group's order
+	factors := make([]int, 0)
+	rest := order
+	for p := 2; p*p <= rest; p++ {
+		if rest%p == 0 {
+			factors = append(factors, p)
+			for rest%p == 0 {
+				rest /= p
+			}
+		}
+	}
+	if rest > 1 {
+		factors = append(factors, rest)
+	}
+
+	// g generates the group if its order is order, and not a proper divisor of it
+	for g := GfElement(1); int(g) <= order; g++ {
+		if powModulo(g, order, poly, m) != 1 {
+			continue
+		}
+		generator := true
+		for _, p := range factors {
+			if powModulo(g, order/p, poly, m) == 1 {
+				generator = false
+				break
+			}
+		}
+		if generator {
+			return g
+		}
+	}
+	return 0
+}
+
+// raise a to the power e without using the log tables
+func powModulo(a GfElement, e int, poly int, m int) GfElement {
+	result := GfElement(1)
+	for ; e > 0; e >>= 1 {
+		if e&0b1 == 1 {
+			result = multiplyModulo(result, a, poly, m)
+		}
+		a = multiplyModulo(a, a, poly, m)
+	}
+	return result
+}
+
 // computes the degree of a given polynomial
 func ComputeDegree(poly int) int {
 	m := 0
@@ -35,7 +181,27 @@ func ComputeDegree(poly int) int {
 	return m
 }
 
+// fields are never modified once built, so each is built once and shared by every caller
+var fieldCache sync.Map // primitive polynomial -> Gf2m
+
+// NewField returns GF(2^m) for the given polynomial, building its tables the first time it is used
+// it panics if the polynomial is reducible or of degree above 24, so polynomials from untrusted input must be checked first
 func NewField(primitivePoly int) Gf2m {
+	if field, ok := fieldCache.Load(primitivePoly); ok {
+		return field.(Gf2m)
+	}
+
+	if ComputeDegree(primitivePoly) > maxFieldDegree || !IsIrreducible(primitivePoly) {
+		panic(fmt.Sprintf("shamir: 0x%x does not define a supported field", primitivePoly))
+	}
+
+	// if another goroutine built the same field first, its tables are used instead
+	field, _ := fieldCache.LoadOrStore(primitivePoly, buildField(primitivePoly))
+	return field.(Gf2m)
+}
+
+// build the log and antilog tables of GF(2^m) by powering a generator
+func buildField(primitivePoly int) Gf2m {
 
 	const q = 2 // will only produce GF(2^m)
 	m := ComputeDegree(primitivePoly)
@@ -53,16 +219,15 @@ func NewField(primitivePoly int) Gf2m {
 		antilogTable:  make([]GfElement, n_elements),
 	}
 
+	lut.generator = findGenerator(primitivePoly, m)
+
 	var poly GfElement = 1
 
 	for power := GfPower(0); int(power) < n_elements-1; power++ {
 		lut.antilogTable[power] = poly
 		lut.logTable[poly] = power
 
-		poly <<= 1
-		if poly&(0b1<<m) != 0 {
-			poly ^= GfElement(primitivePoly)
-		}
+		poly = multiplyModulo(poly, lut.generator, primitivePoly, m)
 	}
 
 	lut.logTable[0] = math.MinInt
@@ -72,6 +237,23 @@ func NewField(primitivePoly int) Gf2m {
 	return lut
 }
 
+// multiply two elements without using the log tables
+func multiplyModulo(a, b GfElement, primitivePoly int, m int) GfElement {
+	c := GfElement(0)
+	for b > 0 {
+		if b&0b1 == 1 {
+			c ^= a
+		}
+		b >>= 1
+
+		a <<= 1
+		if a&(0b1<<m) != 0 {
+			a ^= GfElement(primitivePoly)
+		}
+	}
+	return c
+}
+
 // add two elements in the field
 func (field Gf2m) Add(a, b GfElement) GfElement {
 	return a ^ b
@@ -113,12 +295,56 @@ func (field Gf2m) Divide(a, b GfElement) (GfElement, error) {
 	return field.antilogTable[logc], nil
 }
 
+// compute the multiplicative inverse of a
+func (field Gf2m) Inverse(a GfElement) (GfElement, error) {
+	if a == 0 {
+		return 0, ErrNotInvertible
+	}
+	order := GfPower(field.n_elements - 1)
+	return field.antilogTable[(order-field.logTable[a])%order], nil
+}
+
+// raise a to the power e, where negative powers of nonzero elements are powers of the inverse
+// 0^0 is taken to be 1, and 0^e is 0 for any other e
+func (field Gf2m) Pow(a GfElement, e int) GfElement {
+	if e == 0 {
+		return 1
+	}
+	if a == 0 {
+		return 0
+	}
+
+	order := field.n_elements - 1
+	power := (int(field.logTable[a]) * (e % order)) % order
+	if power < 0 {
+		power += order
+	}
+	return field.antilogTable[power]
+}
+
+// the element whose bits are the coefficients of its polynomial
+func (field Gf2m) Element(i int64) GfElement {
+	return GfElement(i)
+}
+
+// a uniformly random element from a cryptographically secure source
+func (field Gf2m) Random() (GfElement, error) {
+	return field.RandomFrom(crand.Reader)
+}
+
+// a uniformly random element drawn from r
+func (field Gf2m) RandomFrom(r io.Reader) (GfElement, error) {
+	b := make([]byte, 4)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return 0, err
+	}
+	defer Wipe(b)
+
+	// the number of elements is a power of 2, so this is uniform
+	return GfElement(binary.BigEndian.Uint32(b) % uint32(field.n_elements)), nil
+}
+
 // evaluate a polynomial over a field
 func (field Gf2m) EvaluatePolynomial(p []GfElement, x GfElement) (y GfElement) {
-	y = 0
-	for d := len(p) - 1; d > -1; d-- {
-		y = field.Multiply(y, GfElement(x))
-		y = field.Add(y, GfElement(p[d]))
-	}
-	return y
+	return evaluatePolynomial(field, p, x)
 }
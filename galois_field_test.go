@@ -281,3 +281,175 @@ func TestComputeDegree(t *testing.T) {
 		t.Errorf("have %d, want %d", have, want)
 	}
 }
+
+func TestInverse(t *testing.T) {
+	for _, primitivePoly := range []int{0b10011, 0x11b, 0x11d} {
+		field := NewField(primitivePoly)
+		for a := GfElement(1); int(a) < field.GetNelements(); a++ {
+			inverse, err := field.Inverse(a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if have := field.Multiply(a, inverse); have != 1 {
+				t.Errorf("0x%x: %d*%d=1, not %d", primitivePoly, a, inverse, have)
+			}
+		}
+	}
+
+	field := NewField(0b10011)
+	if _, err := field.Inverse(0); err != ErrNotInvertible {
+		t.Errorf("0 should have no inverse, got %v", err)
+	}
+
+	if have, want := field.Multiply(2, 9), GfElement(1); have != want {
+		t.Fatalf("2*9=1, not %d", have)
+	}
+	if have, _ := field.Inverse(2); have != 9 {
+		t.Errorf("1/2=9, not %d", have)
+	}
+}
+
+func TestPow(t *testing.T) {
+	field := NewField(0x11b)
+
+	for a := GfElement(0); int(a) < field.GetNelements(); a++ {
+		want := GfElement(1)
+		for e := 0; e < 10; e++ {
+			if have := field.Pow(a, e); have != want {
+				t.Errorf("%d^%d=%d, not %d", a, e, want, have)
+			}
+			want = field.Multiply(want, a)
+		}
+	}
+
+	// every nonzero element has order dividing 2^m - 1
+	for a := GfElement(1); int(a) < field.GetNelements(); a++ {
+		if have := field.Pow(a, field.GetNelements()-1); have != 1 {
+			t.Errorf("%d^255=1, not %d", a, have)
+		}
+		inverse, _ := field.Inverse(a)
+		if have, want := field.Pow(a, -3), field.Pow(inverse, 3); have != want {
+			t.Errorf("%d^-3=%d, not %d", a, want, have)
+		}
+	}
+
+	if have := field.Pow(0, -1); have != 0 {
+		t.Errorf("0^-1 should be 0, not %d", have)
+	}
+}
+
+func TestNewFieldCached(t *testing.T) {
+	fields := make(chan Gf2m, 8)
+	for range cap(fields) {
+		go func() {
+			fields <- NewField(0x12b)
+		}()
+	}
+
+	// every caller shares the tables of the first field built
+	first := <-fields
+	for range cap(fields) - 1 {
+		field := <-fields
+		if &field.logTable[0] != &first.logTable[0] || &field.antilogTable[0] != &first.antilogTable[0] {
+			t.Error("NewField should reuse the tables it already built")
+		}
+	}
+}
+
+func TestFieldByName(t *testing.T) {
+	for name, want := range map[string]int{
+		"aes":   0x11b,
+		"rs":    0x11d,
+		"13":    0x13,
+		"11d":   0x11d,
+		"0x12b": 0x12b,
+	} {
+		field, err := FieldByName(name)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if field.GetPrimitivePoly() != want {
+			t.Errorf("%s should use polynomial 0x%x, not 0x%x", name, want, field.GetPrimitivePoly())
+		}
+	}
+
+	if field, _ := FieldByName("13"); field.GetNelements() != 16 {
+		t.Errorf("0x13 should give a field with 16 elements, not %d", field.GetNelements())
+	}
+
+	for name, want := range map[string]error{
+		"rijndael": ErrUnknownField,
+		"gf16":     ErrUnknownField,
+		"":         ErrUnknownField,
+		"1":        ErrUnknownField,
+		"11c":      ErrNonPrimitivePolynomial,
+	} {
+		if _, err := FieldByName(name); err != want {
+			t.Errorf("%q should fail with %v, got %v", name, want, err)
+		}
+	}
+}
+
+func TestIsIrreducible(t *testing.T) {
+	// there are 3 irreducible polynomials of degree 4 and 30 of degree 8
+	for m, want := range map[int]int{1: 2, 2: 1, 3: 2, 4: 3, 8: 30} {
+		count := 0
+		for poly := 1 << m; poly < 1<<(m+1); poly++ {
+			if IsIrreducible(poly) {
+				count++
+			}
+		}
+		if count != want {
+			t.Errorf("%d irreducible polynomials of degree %d, not %d", count, m, want)
+		}
+	}
+
+	for poly, want := range map[int]bool{
+		0x11b:  true,  // AES, which is not primitive
+		0x11d:  true,  // primitive
+		0b101:  false, // (x + 1)^2
+		0x11c:  false, // divisible by x
+		0x1:    false,
+		0x1002: false,
+	} {
+		if IsIrreducible(poly) != want {
+			t.Errorf("IsIrreducible(0x%x) should be %v", poly, want)
+		}
+	}
+}
+
+func TestIrreducibleFields(t *testing.T) {
+	for poly := 1 << 8; poly < 1<<9; poly++ {
+		if !IsIrreducible(poly) {
+			continue
+		}
+		field := NewField(poly)
+
+		// the tables must be a bijection, and agree with multiplication without them
+		seen := make(map[GfElement]any)
+		for a := GfElement(1); a < 256; a++ {
+			if field.antilogTable[field.logTable[a]] != a {
+				t.Fatalf("0x%x: exp^log(%d) != %d", poly, a, a)
+			}
+			seen[field.antilogTable[a-1]] = nil
+			for b := GfElement(1); b < 256; b += 17 {
+				if have, want := field.Multiply(a, b), multiplyModulo(a, b, poly, 8); have != want {
+					t.Fatalf("0x%x: %d*%d=%d, not %d", poly, a, b, want, have)
+				}
+			}
+		}
+		if len(seen) != 255 {
+			t.Errorf("0x%x: the generator should reach all 255 nonzero elements, not %d", poly, len(seen))
+		}
+	}
+
+	// x doesn't generate the AES field, so another generator is found
+	if g := NewField(0x11b).generator; g != 0b11 {
+		t.Errorf("the smallest generator of the AES field is 3, not %d", g)
+	}
+
+	if _, err := NewShamirSecret(0b101, 3, 2, []byte{1}); err != ErrReduciblePolynomial {
+		t.Errorf("a reducible polynomial should fail, got %v", err)
+	}
+}
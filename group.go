@@ -0,0 +1,219 @@
+package shamir
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+
+	"filippo.io/edwards25519"
+)
+
+// scalar shares live in the prime-order group of edwards25519 instead of GF(2^m),
+// so that commitments to them can be published without revealing them
+// scalar shares look like shamir-<id>-ed25519-<x>-<y>, where y is a little-endian scalar
+
+const GroupEd25519 string = "ed25519"
+
+var ErrInvalidScalar error = errors.New("share is not a canonical ed25519 scalar")
+var ErrInvalidPoint error = errors.New("invalid ed25519 point")
+
+// the scalar corresponding to a share index
+func scalarFromIndex(i int) *edwards25519.Scalar {
+	b := make([]byte, 32)
+	binary.LittleEndian.PutUint64(b, uint64(i))
+	s, err := edwards25519.NewScalar().SetCanonicalBytes(b)
+	if err != nil {
+		panic(err) // unreachable, since i < 2^64 is always canonical
+	}
+	return s
+}
+
+func randomScalar() (*edwards25519.Scalar, error) {
+	b := make([]byte, 64)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	defer Wipe(b)
+	return edwards25519.NewScalar().SetUniformBytes(b)
+}
+
+// hash the parts with a domain separation tag into a scalar
+func hashToScalar(tag string, parts ...[]byte) *edwards25519.Scalar {
+	h := sha512.New()
+	h.Write([]byte(tag))
+	for _, part := range parts {
+		h.Write(binary.BigEndian.AppendUint32(nil, uint32(len(part))))
+		h.Write(part)
+	}
+	s, err := edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+	if err != nil {
+		panic(err) // unreachable, since SHA-512 digests are 64 bytes
+	}
+	return s
+}
+
+func parsePoint(b []byte) (*edwards25519.Point, error) {
+	p, err := new(edwards25519.Point).SetBytes(b)
+	if err != nil {
+		return nil, ErrInvalidPoint
+	}
+	return p, nil
+}
+
+// evaluate the polynomial with the given coefficients at x
+func evaluateScalarPolynomial(coefficients []*edwards25519.Scalar, x *edwards25519.Scalar) *edwards25519.Scalar {
+	y := edwards25519.NewScalar()
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		y.MultiplyAdd(y, x, coefficients[i])
+	}
+	return y
+}
+
+// evaluate the polynomial committed to by the given points at x, returning f(x)*B
+func evaluateCommitments(commitments []*edwards25519.Point, x *edwards25519.Scalar) *edwards25519.Point {
+	y := edwards25519.NewIdentityPoint()
+	for i := len(commitments) - 1; i >= 0; i-- {
+		y.ScalarMult(x, y)
+		y.Add(y, commitments[i])
+	}
+	return y
+}
+
+// the Lagrange coefficient for the share at index i, to interpolate at zero from the shares at indices
+func lagrangeCoefficient(i int, indices []int) (*edwards25519.Scalar, error) {
+	return lagrangeCoefficientAt(i, indices, 0)
+}
+
+// the Lagrange coefficient for the share at index i, to interpolate at x from the shares at indices
+func lagrangeCoefficientAt(i int, indices []int, x int) (*edwards25519.Scalar, error) {
+	num := scalarFromIndex(1)
+	den := scalarFromIndex(1)
+	xi := scalarFromIndex(i)
+	xs := scalarFromIndex(x)
+
+	for _, j := range indices {
+		if j == i {
+			continue
+		}
+		xj := scalarFromIndex(j)
+		num.Multiply(num, edwards25519.NewScalar().Subtract(xs, xj))
+		den.Multiply(den, edwards25519.NewScalar().Subtract(xi, xj))
+	}
+
+	if den.Equal(edwards25519.NewScalar()) == 1 {
+		return nil, ErrDuplicateShare
+	}
+
+	return num.Multiply(num, edwards25519.NewScalar().Invert(den)), nil
+}
+
+// NewScalarShare makes a share of an ed25519 scalar
+func NewScalarShare(secret_id string, x int, y *edwards25519.Scalar) Share {
+	b := y.Bytes()
+	ys := make([]GfElement, len(b))
+	for i := range b {
+		ys[i] = GfElement(b[i])
+	}
+
+	share := NewShare(secret_id, 0, GfElement(x), ys)
+	share.group = GroupEd25519
+	return share
+}
+
+// split the scalar into n shares with threshold k, at x = 1, ..., n
+func splitScalar(secret_id string, secret *edwards25519.Scalar, n, k int) ([]Share, error) {
+	coefficients := make([]*edwards25519.Scalar, k)
+	coefficients[0] = edwards25519.NewScalar().Set(secret)
+	defer func() {
+		for _, a := range coefficients {
+			if a != nil {
+				a.Set(edwards25519.NewScalar())
+			}
+		}
+	}()
+	for i := 1; i < k; i++ {
+		var err error
+		coefficients[i], err = randomScalar()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	shares := make([]Share, n)
+	for i := range shares {
+		y := evaluateScalarPolynomial(coefficients, scalarFromIndex(i+1))
+		shares[i] = NewScalarShare(secret_id, i+1, y)
+		shares[i].threshold = k
+		y.Set(edwards25519.NewScalar())
+	}
+
+	return shares, nil
+}
+
+// Scalar returns the value of a scalar share
+func (share Share) Scalar() (*edwards25519.Scalar, error) {
+	if share.group != GroupEd25519 {
+		return nil, ErrInvalidScalar
+	}
+
+	s, err := edwards25519.NewScalar().SetCanonicalBytes(share.yBytes())
+	if err != nil {
+		return nil, ErrInvalidScalar
+	}
+	return s, nil
+}
+
+// interpolate scalar shares at zero
+func recoverScalar(shares []Share) (*edwards25519.Scalar, error) {
+	return interpolateScalar(shares, 0)
+}
+
+// evaluate the polynomial through the scalar shares at x
+func interpolateScalar(shares []Share, x int) (*edwards25519.Scalar, error) {
+	indices := make([]int, len(shares))
+	for i, share := range shares {
+		indices[i] = int(share.x)
+	}
+
+	result := edwards25519.NewScalar()
+	for i, share := range shares {
+		y, err := share.Scalar()
+		if err != nil {
+			return nil, err
+		}
+
+		ell, err := lagrangeCoefficientAt(indices[i], indices, x)
+		if err != nil {
+			return nil, err
+		}
+
+		result.MultiplyAdd(y, ell, result)
+	}
+
+	return result, nil
+}
+
+// recover the scalar shared by the shares into memory provided by alloc
+func recoverScalarSecret(shares []Share, o options, alloc func(n int) ([]byte, error)) ([]byte, error) {
+	scalar, err := recoverScalar(shares)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := alloc(32)
+	if err != nil {
+		return nil, err
+	}
+	copy(secret, scalar.Bytes())
+	scalar.Set(edwards25519.NewScalar())
+
+	if o.commitment != "" {
+		if err := VerifyRecovered(secret, o.commitment); err != nil {
+			Wipe(secret)
+			return nil, err
+		}
+	}
+
+	return secret, nil
+}
@@ -0,0 +1,120 @@
+package shamir
+
+import (
+	"errors"
+	"math/big"
+)
+
+var ErrXOutOfRange error = errors.New("x coordinate is not an element of the shares' field")
+var ErrUnsupportedGroup error = errors.New("operation is only supported for shares over GF(2^m)")
+
+// Interpolate evaluates the polynomial through the shares at x, giving the share at x
+// interpolating at 0 gives the secret, and interpolating at a new x mints a new share
+// the new share records the shares' threshold but isn't signed
+func Interpolate(shares []Share, x GfElement) (Share, error) {
+	if err := ValidateShares(shares); err != nil {
+		return Share{}, err
+	}
+
+	share := Share{
+		secret_id:     shares[0].secret_id,
+		primitivePoly: shares[0].primitivePoly,
+		group:         shares[0].group,
+		scheme:        shares[0].scheme,
+		threshold:     sharesThreshold(shares),
+		x:             x,
+	}
+
+	switch shares[0].group {
+	case "":
+		if x < 0 || int(x) >= NewField(int(shares[0].primitivePoly)).GetNelements() {
+			return Share{}, ErrXOutOfRange
+		}
+
+		share.y = make([]GfElement, len(shares[0].y))
+		if err := interpolateGf2m(shares, x, share.y); err != nil {
+			return Share{}, err
+		}
+
+	case GroupEd25519:
+		if x < 0 {
+			return Share{}, ErrXOutOfRange
+		}
+
+		y, err := interpolateScalar(shares, int(x))
+		if err != nil {
+			return Share{}, err
+		}
+		scalarShare := NewScalarShare(share.secret_id, int(x), y)
+		share.y = scalarShare.y
+
+	default:
+		field, err := PrimeFieldByName(shares[0].group)
+		if err != nil {
+			return Share{}, err
+		}
+		if x < 0 || big.NewInt(int64(x)).Cmp(field.GetModulus()) >= 0 {
+			return Share{}, ErrXOutOfRange
+		}
+
+		y, err := interpolatePrime(field, shares, field.Element(int64(x)))
+		if err != nil {
+			return Share{}, err
+		}
+		b := field.Encode(y)
+		wipeInt(y)
+		share.y = make([]GfElement, len(b))
+		for i := range b {
+			share.y[i] = GfElement(b[i])
+		}
+		Wipe(b)
+	}
+
+	return share, nil
+}
+
+// InterpolatePolynomials finds the full sharing polynomial for each byte of the secret from shares over GF(2^m)
+// the constant terms are the secret, so the polynomials must be handled as carefully as the secret itself
+func InterpolatePolynomials(shares []Share) ([]Polynomial, error) {
+	if err := ValidateShares(shares); err != nil {
+		return nil, err
+	}
+	if shares[0].group != "" {
+		return nil, ErrUnsupportedGroup
+	}
+
+	field := NewField(int(shares[0].primitivePoly))
+
+	xs := make([]GfElement, len(shares))
+	for s, share := range shares {
+		xs[s] = share.x
+	}
+
+	ys := make([]GfElement, len(shares))
+	defer wipeElements(ys)
+
+	polynomials := make([]Polynomial, len(shares[0].y))
+	for i := range polynomials {
+		for s, share := range shares {
+			ys[s] = share.y[i]
+		}
+
+		var err error
+		polynomials[i], err = InterpolatePolynomial(field, xs, ys)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return polynomials, nil
+}
+
+// the threshold recorded by the shares, or 0 if none of them record it
+func sharesThreshold(shares []Share) int {
+	for _, share := range shares {
+		if share.threshold != 0 {
+			return share.threshold
+		}
+	}
+	return 0
+}
@@ -0,0 +1,130 @@
+package shamir
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestInterpolate(t *testing.T) {
+	secret := []byte("interpolate me")
+	s, err := NewShamirSecret(0x11b, 5, 3, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares := s.GetShares()
+
+	// interpolating at 0 gives the secret
+	zero, err := Interpolate(shares[:3], 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have := zero.yBytes(); !bytes.Equal(have, secret) {
+		t.Errorf("interpolating at 0 gave %q, not %q", have, secret)
+	}
+
+	// interpolating at an existing x reproduces that share
+	minted, err := Interpolate(shares[:3], 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := minted.String(), shares[4].String(); have != want {
+		t.Errorf("minted share %s, not %s", have, want)
+	}
+
+	// a minted share can stand in for any other
+	minted, err = Interpolate(shares[1:4], 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if minted.GetThreshold() != 3 || minted.GetSecretId() != s.GetId() {
+		t.Errorf("minted share %s doesn't match the secret's metadata", minted)
+	}
+	recovered, err := RecoverSecret([]Share{shares[0], minted, shares[4]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		t.Errorf("recovered %q with a minted share, not %q", recovered, secret)
+	}
+
+	if _, err := Interpolate(shares[:3], 256); err != ErrXOutOfRange {
+		t.Errorf("x outside GF(256) should fail, got %v", err)
+	}
+	if _, err := Interpolate(append(shares[:2:2], shares[1]), 7); err != ErrDuplicateShare {
+		t.Errorf("duplicate shares should fail, got %v", err)
+	}
+	if _, err := Interpolate(nil, 7); err != ErrNoShares {
+		t.Errorf("no shares should fail, got %v", err)
+	}
+}
+
+func TestInterpolatePrimeAndScalar(t *testing.T) {
+	field, err := PrimeFieldByName("p256")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewShamirSecret(0, 4, 2, []byte{0x12, 0x34}, WithPrimeField(field))
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares := s.GetShares()
+
+	minted, err := Interpolate(shares[:2], 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := minted.String(), shares[3].String(); have != want {
+		t.Errorf("minted share %s, not %s", have, want)
+	}
+
+	keyShares, _, err := GenerateThresholdKey("INTERP", 4, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	minted, err = Interpolate(keyShares[1:], 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := minted.String(), keyShares[0].String(); have != want {
+		t.Errorf("minted scalar share %s, not %s", have, want)
+	}
+}
+
+func TestInterpolatePolynomials(t *testing.T) {
+	secret := []byte{7, 99, 250}
+	s, err := NewShamirSecret(0x11d, 6, 4, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares := s.GetShares()
+
+	polynomials, err := InterpolatePolynomials(shares[2:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(polynomials) != len(secret) {
+		t.Fatalf("found %d polynomials, not %d", len(polynomials), len(secret))
+	}
+
+	for i, p := range polynomials {
+		if p.Degree() > 3 {
+			t.Errorf("polynomial %d has degree %d, more than threshold-1", i, p.Degree())
+		}
+		if have := p.Coefficient(0); have != GfElement(secret[i]) {
+			t.Errorf("constant term %d is %d, not %d", i, have, secret[i])
+		}
+		for _, share := range shares {
+			if have, want := p.Evaluate(share.x), share.y[i]; have != want {
+				t.Errorf("polynomial %d at %d is %d, not %d", i, share.x, have, want)
+			}
+		}
+	}
+
+	keyShares, _, err := GenerateThresholdKey("INTERP", 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := InterpolatePolynomials(keyShares); err != ErrUnsupportedGroup {
+		t.Errorf("scalar shares should be unsupported, got %v", err)
+	}
+}
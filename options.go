@@ -0,0 +1,25 @@
+package shamir
+
+import "crypto/ed25519"
+
+// configures NewShamirSecret and RecoverSecret
+type Option func(*options)
+
+type options struct {
+	dealerKey      ed25519.PrivateKey
+	trustedDealers []ed25519.PublicKey
+	commitment     string
+	primeField     *Fp    // share over GF(p) instead of GF(2^m)
+	scheme         Scheme // disperse the secret instead of using Shamir's scheme
+	xs             []GfElement
+	randomXs       bool
+	seed           []byte // derive everything chosen at random from the seed and the secret
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
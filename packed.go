@@ -0,0 +1,400 @@
+package shamir
+
+import (
+	"bytes"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// packed (ramp) sharing embeds several bytes of the secret in each polynomial, so shares are a fraction of the secret's size
+// with privacy threshold t and threshold k, each polynomial has degree k-1 and carries k-t bytes of the secret
+// any t shares reveal nothing and any k shares recover the secret, but between t and k shares leak part of it
+// the secret bytes are the polynomial's values at the highest x values of the field, and t random values sit just below them
+// packed shares look like shamirpacked-<id>-<poly>-<privacy threshold>-<threshold>-<x>-<y>
+
+const PackedSharePrefix string = "shamirpacked"
+
+var ErrInvalidPacking error = errors.New("privacy threshold must be at least 1 and less than the threshold")
+var ErrTooManyShares error = errors.New("too many shares; packing reserves the highest x values of the field for the secret")
+var ErrMismatchedPacking error = errors.New("packed shares record different thresholds")
+var ErrInvalidPadding error = errors.New("recovered secret is malformed; a share is wrong, or there are fewer than the threshold")
+var ErrUnsupportedPackingOption error = errors.New("packed shares can't be signed or checked against trusted dealers, placed at chosen x coordinates, shared over a prime field, or dispersed")
+
+var packedRegexp = regexp.MustCompile(`shamirpacked-(\w+)-([0-9a-f]+)-(\d+)-(\d+)-(\d+)-([\w\+\/]+)`)
+
+type PackedShare struct {
+	secret_id     string
+	primitivePoly int64
+	privacy       int         // number of shares that reveal nothing about the secret
+	threshold     int         // number of shares needed to reconstruct the secret
+	x             GfElement   // x coordinate
+	y             []GfElement // y coordinates, one for each polynomial
+}
+
+type PackedSecret struct {
+	id         string
+	commitment string
+	shares     []PackedShare
+}
+
+func (packed PackedSecret) GetId() string {
+	return packed.id
+}
+
+// salted hash of the secret, to publish so the reconstructed secret can be checked
+func (packed PackedSecret) GetCommitment() string {
+	return packed.commitment
+}
+
+func (packed PackedSecret) GetShares() []PackedShare {
+	return packed.shares
+}
+
+// Wipe overwrites the y values of every share
+func (packed *PackedSecret) Wipe() {
+	for _, share := range packed.shares {
+		share.Wipe()
+	}
+}
+
+// NewPackedSecret splits the secret into nshares packed shares, where any privacy shares reveal nothing and any threshold shares recover it
+// WithSeed makes the shares reproducible; options packed shares can't honor give ErrUnsupportedPackingOption
+func NewPackedSecret(primitivePoly int, nshares int, privacy int, threshold int, secret []byte, opts ...Option) (*PackedSecret, error) {
+	o := newOptions(opts)
+
+	if o.dealerKey != nil || len(o.trustedDealers) > 0 || len(o.xs) > 0 || o.randomXs || o.primeField != nil || o.scheme != SchemeShamir {
+		return nil, ErrUnsupportedPackingOption
+	}
+	if threshold > nshares {
+		return nil, ErrThresholdTooLarge
+	}
+	if privacy < 1 || privacy >= threshold {
+		return nil, ErrInvalidPacking
+	}
+	if (primitivePoly & 0b1) != 1 {
+		return nil, ErrNonPrimitivePolynomial
+	}
+	if !IsIrreducible(primitivePoly) {
+		return nil, ErrReduciblePolynomial
+	}
+	if ComputeDegree(primitivePoly) != 8 {
+		return nil, ErrUnsupportedField
+	}
+
+	field := NewField(primitivePoly)
+	packing := threshold - privacy
+	if nshares+threshold >= field.GetNelements() {
+		return nil, ErrTooManyShares
+	}
+
+	// the label's parameters keep seeded packed shares unrelated to seeded ordinary shares of the same secret
+	r, err := o.randomness(secret, fmt.Sprintf("%s-%x-%d", PackedSharePrefix, primitivePoly, privacy), nshares, threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	idbytes := make([]byte, 5)
+	if _, err := io.ReadFull(r, idbytes); err != nil {
+		return nil, err
+	}
+
+	commitment, err := newCommitment(r, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	packed := &PackedSecret{
+		id:         base32.StdEncoding.EncodeToString(idbytes),
+		commitment: commitment,
+		shares:     make([]PackedShare, nshares),
+	}
+
+	paddedBuf, err := padSecret(secret, packing)
+	if err != nil {
+		return nil, err
+	}
+	defer paddedBuf.Close()
+	padded := paddedBuf.Bytes()
+	nblocks := len(padded) / packing
+
+	for i := range packed.shares {
+		packed.shares[i] = PackedShare{
+			secret_id:     packed.id,
+			primitivePoly: int64(primitivePoly),
+			privacy:       privacy,
+			threshold:     threshold,
+			x:             GfElement(i + 1),
+			y:             make([]GfElement, nblocks),
+		}
+	}
+
+	// each polynomial is defined by its values at the reserved x values
+	xs := reservedXs(field, threshold)
+	// the values at the reserved x values include the secret, so they are locked
+	ys, ysBuf, err := lockedElements(threshold)
+	if err != nil {
+		return nil, err
+	}
+	defer ysBuf.Close()
+
+	for b := range nblocks {
+		for j := range packing {
+			ys[j] = GfElement(padded[b*packing+j])
+		}
+		for j := packing; j < threshold; j++ {
+			ys[j], err = field.RandomFrom(r)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for _, share := range packed.shares {
+			share.y[b], err = lagrangeInterpolate(field, xs, ys, share.x)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return packed, nil
+}
+
+// the x values defining each polynomial, counting down from the highest element of the field
+// the first k-t carry the secret and the rest are random
+func reservedXs(field Gf2m, threshold int) []GfElement {
+	xs := make([]GfElement, threshold)
+	for i := range xs {
+		xs[i] = GfElement(field.GetNelements() - 1 - i)
+	}
+	return xs
+}
+
+// pad the secret to a multiple of the block size with 0x80 followed by zeros, so its length can be recovered
+func padSecret(secret []byte, blockSize int) (*SecretBuffer, error) {
+	n := (len(secret)/blockSize + 1) * blockSize
+	padded, err := NewSecretBuffer(n)
+	if err != nil {
+		return nil, err
+	}
+	copy(padded.Bytes(), secret)
+	padded.Bytes()[len(secret)] = 0x80
+	return padded, nil
+}
+
+// the length of the secret before padSecret
+func unpaddedLength(padded []byte) (int, error) {
+	i := bytes.LastIndexByte(padded, 0x80)
+	if i < 0 {
+		return 0, ErrInvalidPadding
+	}
+	for _, b := range padded[i+1:] {
+		if b != 0 {
+			return 0, ErrInvalidPadding
+		}
+	}
+	return i, nil
+}
+
+// ValidatePackedShares checks that the packed shares could all belong to the same secret, and that there are enough to recover it
+func ValidatePackedShares(shares []PackedShare) error {
+	if len(shares) == 0 {
+		return ErrNoShares
+	}
+
+	existingxs := make(map[GfElement]any, 0)
+	for _, share := range shares {
+		if share.secret_id != shares[0].secret_id {
+			return ErrMismatchedSecretID
+		}
+		if share.primitivePoly != shares[0].primitivePoly {
+			return ErrMismatchedPolynomial
+		}
+		if share.privacy != shares[0].privacy || share.threshold != shares[0].threshold {
+			return ErrMismatchedPacking
+		}
+		if len(share.y) != len(shares[0].y) {
+			return ErrInconsistentLength
+		}
+		if _, ok := existingxs[share.x]; ok {
+			return ErrDuplicateShare
+		}
+		existingxs[share.x] = nil
+	}
+
+	if err := checkSharePolynomial(shares[0].primitivePoly); err != nil {
+		return err
+	}
+	if shares[0].privacy < 1 || shares[0].privacy >= shares[0].threshold {
+		return ErrInvalidPacking
+	}
+
+	// x values must be in the field, and not among those reserved for the secret and the random values
+	lowestReserved := 1<<8 - shares[0].threshold
+	for _, share := range shares {
+		if share.x < 1 || int(share.x) >= lowestReserved {
+			return ErrXOutOfRange
+		}
+	}
+
+	if len(shares) < shares[0].threshold {
+		return ErrTooFewShares
+	}
+
+	return nil
+}
+
+// RecoverPackedSecret recovers the secret from at least threshold packed shares, checking it against WithCommitment if given
+// packed shares aren't signed, so WithTrustedDealer gives ErrUnsupportedPackingOption rather than being ignored
+func RecoverPackedSecret(shares []PackedShare, opts ...Option) ([]byte, error) {
+	o := newOptions(opts)
+
+	if len(o.trustedDealers) > 0 {
+		return nil, ErrUnsupportedPackingOption
+	}
+
+	if err := ValidatePackedShares(shares); err != nil {
+		return nil, err
+	}
+
+	field := NewField(int(shares[0].primitivePoly))
+	threshold := shares[0].threshold
+	packing := threshold - shares[0].privacy
+	secretXs := reservedXs(field, threshold)[:packing]
+
+	// only threshold shares are needed, since the polynomials have degree threshold-1
+	xs := make([]GfElement, threshold)
+	for s := range xs {
+		xs[s] = shares[s].x
+	}
+
+	ys := make([]GfElement, threshold)
+	defer wipeElements(ys)
+
+	paddedBuf, err := NewSecretBuffer(len(shares[0].y) * packing)
+	if err != nil {
+		return nil, err
+	}
+	defer paddedBuf.Close()
+	padded := paddedBuf.Bytes()
+
+	for b := range shares[0].y {
+		for s := range ys {
+			ys[s] = shares[s].y[b]
+		}
+
+		for j, x := range secretXs {
+			y, err := lagrangeInterpolate(field, xs, ys, x)
+			if err != nil {
+				return nil, err
+			}
+			padded[b*packing+j] = byte(y)
+		}
+	}
+
+	n, err := unpaddedLength(padded)
+	if err != nil {
+		return nil, err
+	}
+	secret := make([]byte, n)
+	copy(secret, padded)
+
+	if o.commitment != "" {
+		if err := VerifyRecovered(secret, o.commitment); err != nil {
+			Wipe(secret)
+			return nil, err
+		}
+	}
+
+	return secret, nil
+}
+
+func NewPackedSharesFromString(input string) ([]PackedShare, error) {
+	shares := make([]PackedShare, 0)
+
+	for _, match := range packedRegexp.FindAllStringSubmatch(input, -1) {
+		primitivePoly, err := strconv.ParseInt(match[2], 16, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		privacy, err := strconv.Atoi(match[3])
+		if err != nil {
+			return nil, err
+		}
+
+		threshold, err := strconv.Atoi(match[4])
+		if err != nil {
+			return nil, err
+		}
+
+		x, err := strconv.ParseInt(match[5], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		ydata, err := base64.RawStdEncoding.DecodeString(match[6])
+		if err != nil {
+			return nil, err
+		}
+
+		y := make([]GfElement, len(ydata))
+		for i := range ydata {
+			y[i] = GfElement(ydata[i])
+		}
+		Wipe(ydata)
+
+		shares = append(shares, PackedShare{
+			secret_id:     match[1],
+			primitivePoly: primitivePoly,
+			privacy:       privacy,
+			threshold:     threshold,
+			x:             GfElement(x),
+			y:             y,
+		})
+	}
+
+	return shares, nil
+}
+
+// IsPacked reports whether the input contains packed shares
+func IsPacked(input string) bool {
+	return packedRegexp.MatchString(input)
+}
+
+func (share PackedShare) ShareLabel() string {
+	return fmt.Sprintf("%s-%s-%x-%d-%d-%d", PackedSharePrefix, share.secret_id, share.primitivePoly, share.privacy, share.threshold, share.x)
+}
+
+func (share PackedShare) String() string {
+	b := make([]byte, len(share.y))
+	for i := range b {
+		b[i] = byte(share.y[i])
+	}
+	defer Wipe(b)
+
+	return fmt.Sprintf("%s-%s", share.ShareLabel(), base64.RawStdEncoding.EncodeToString(b))
+}
+
+func (share PackedShare) GetSecretId() string {
+	return share.secret_id
+}
+
+// number of shares that reveal nothing about the secret
+func (share PackedShare) GetPrivacyThreshold() int {
+	return share.privacy
+}
+
+// number of shares needed to reconstruct the secret
+func (share PackedShare) GetThreshold() int {
+	return share.threshold
+}
+
+// Wipe overwrites the share's y values with zeros once it is no longer needed
+func (share PackedShare) Wipe() {
+	wipeElements(share.y)
+}
@@ -0,0 +1,179 @@
+package shamir
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"strings"
+	"testing"
+)
+
+func TestPackedSecret(t *testing.T) {
+	secret := []byte("a long secret that packs three bytes into each polynomial")
+	packed, err := NewPackedSecret(0x11d, 7, 2, 5, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares := packed.GetShares()
+
+	// three bytes per polynomial, plus at least one byte of padding
+	if have, want := len(shares[0].y), len(secret)/3+1; have != want {
+		t.Errorf("shares should be %d bytes, not %d", want, have)
+	}
+
+	for _, subset := range [][]PackedShare{shares[:5], shares[2:], {shares[6], shares[0], shares[3], shares[5], shares[1]}} {
+		recovered, err := RecoverPackedSecret(subset, WithCommitment(packed.GetCommitment()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(recovered, secret) {
+			t.Errorf("recovered %q, not %q", recovered, secret)
+		}
+	}
+
+	if _, err := RecoverPackedSecret(shares[:4]); err != ErrTooFewShares {
+		t.Errorf("fewer than k shares should fail, got %v", err)
+	}
+}
+
+func TestPackedPadding(t *testing.T) {
+	for n := 0; n < 10; n++ {
+		secret := bytes.Repeat([]byte{0x80}, n)
+		packed, err := NewPackedSecret(0x11b, 4, 1, 4, secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		recovered, err := RecoverPackedSecret(packed.GetShares())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(recovered, secret) {
+			t.Errorf("recovered %x, not %x", recovered, secret)
+		}
+	}
+}
+
+func TestPackedShareString(t *testing.T) {
+	packed, err := NewPackedSecret(0x11d, 5, 1, 3, []byte("round trip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	strs := make([]string, 0)
+	for _, share := range packed.GetShares() {
+		if !strings.HasPrefix(share.String(), "shamirpacked-"+packed.GetId()+"-11d-1-3-") {
+			t.Errorf("unexpected label %s", share.String())
+		}
+		strs = append(strs, share.String())
+	}
+	input := strings.Join(strs, "\n")
+
+	if !IsPacked(input) {
+		t.Error("packed shares should be recognized")
+	}
+
+	// packed shares mustn't be mistaken for ordinary shares
+	if shares, err := NewSharesFromString(input); err != nil || len(shares) != 0 {
+		t.Errorf("packed shares were parsed as ordinary shares: %v", shares)
+	}
+
+	parsed, err := NewPackedSharesFromString(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recovered, err := RecoverPackedSecret(parsed[2:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(recovered) != "round trip" {
+		t.Errorf("recovered %q", recovered)
+	}
+}
+
+func TestPackedParameters(t *testing.T) {
+	if _, err := NewPackedSecret(0x11d, 5, 3, 3, []byte("x")); err != ErrInvalidPacking {
+		t.Errorf("privacy threshold equal to threshold should fail, got %v", err)
+	}
+	if _, err := NewPackedSecret(0x11d, 5, 0, 3, []byte("x")); err != ErrInvalidPacking {
+		t.Errorf("privacy threshold 0 should fail, got %v", err)
+	}
+	if _, err := NewPackedSecret(0x11d, 250, 2, 10, []byte("x")); err != ErrTooManyShares {
+		t.Errorf("shares overlapping the reserved x values should fail, got %v", err)
+	}
+	if _, err := NewPackedSecret(0x11d, 3, 1, 4, []byte("x")); err != ErrThresholdTooLarge {
+		t.Errorf("threshold above n should fail, got %v", err)
+	}
+}
+
+func TestPackedHostileShares(t *testing.T) {
+	tests := []struct {
+		input string
+		err   error
+	}{
+		{"shamirpacked-AAAA-11d-1-2-300-AAAA shamirpacked-AAAA-11d-1-2-301-AAAA", ErrXOutOfRange},
+		{"shamirpacked-AAAA-11d-1-2-0-AAAA shamirpacked-AAAA-11d-1-2-1-AAAA", ErrXOutOfRange},
+		{"shamirpacked-AAAA-11d-1-2-1-AAAA shamirpacked-AAAA-11d-1-2-254-AAAA", ErrXOutOfRange},
+		{"shamirpacked-AAAA-3-1-2-1-AAAA shamirpacked-AAAA-3-1-2-2-AAAA", ErrUnsupportedField},
+		{"shamirpacked-AAAA-101-1-2-1-AAAA shamirpacked-AAAA-101-1-2-2-AAAA", ErrReduciblePolynomial},
+		{"shamirpacked-AAAA-7fffffff-1-2-1-AAAA shamirpacked-AAAA-7fffffff-1-2-2-AAAA", ErrUnsupportedField},
+		{"shamirpacked-AAAA-11d-1-999999-1-AAAA shamirpacked-AAAA-11d-1-999999-2-AAAA", ErrXOutOfRange},
+	}
+
+	for _, test := range tests {
+		shares, err := NewPackedSharesFromString(test.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := RecoverPackedSecret(shares); err != test.err {
+			t.Errorf("%s: have %v, want %v", test.input, err, test.err)
+		}
+	}
+}
+
+func TestPackedOptions(t *testing.T) {
+	seed := bytes.Repeat([]byte{3}, 32)
+	secret := []byte("packed the same way twice")
+
+	a, err := NewPackedSecret(0x11d, 5, 1, 3, secret, WithSeed(seed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewPackedSecret(0x11d, 5, 1, 3, secret, WithSeed(seed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.GetId() != b.GetId() || a.GetCommitment() != b.GetCommitment() || a.GetShares()[0].String() != b.GetShares()[0].String() {
+		t.Error("the same seed and secret gave different packed shares")
+	}
+
+	// seeded packed shares are unrelated to seeded ordinary shares
+	s, err := NewShamirSecret(0x11d, 5, 3, secret, WithSeed(seed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.GetId() == a.GetId() {
+		t.Error("packed and ordinary shares from the same seed share an ID")
+	}
+
+	recovered, err := RecoverPackedSecret(a.GetShares()[2:], WithCommitment(a.GetCommitment()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		t.Errorf("have %q, want %q", recovered, secret)
+	}
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RecoverPackedSecret(a.GetShares(), WithTrustedDealer(pub)); err != ErrUnsupportedPackingOption {
+		t.Errorf("packed shares can't be checked against a dealer, got %v", err)
+	}
+	for _, opt := range []Option{WithDealerKey(priv), WithRandomXs(), WithScheme(SchemeIda)} {
+		if _, err := NewPackedSecret(0x11d, 5, 1, 3, secret, opt); err != ErrUnsupportedPackingOption {
+			t.Errorf("unsupported option should fail, got %v", err)
+		}
+	}
+}
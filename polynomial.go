@@ -0,0 +1,261 @@
+package shamir
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrZeroPolynomial error = errors.New("division by the zero polynomial")
+
+// a polynomial with coefficients in GF(2^m)
+// coefficients are stored constant term first, with no trailing zeros, so the zero polynomial has none
+type Polynomial struct {
+	field        Gf2m
+	coefficients []GfElement
+}
+
+// create a polynomial from its coefficients, constant term first
+func NewPolynomial(field Gf2m, coefficients ...GfElement) Polynomial {
+	c := make([]GfElement, len(coefficients))
+	copy(c, coefficients)
+	return Polynomial{field: field, coefficients: c}.trim()
+}
+
+// drop zero coefficients above the degree
+func (p Polynomial) trim() Polynomial {
+	n := len(p.coefficients)
+	for n > 0 && p.coefficients[n-1] == 0 {
+		n--
+	}
+	p.coefficients = p.coefficients[:n]
+	return p
+}
+
+func (p Polynomial) GetField() Gf2m {
+	return p.field
+}
+
+// the coefficients, constant term first
+func (p Polynomial) Coefficients() []GfElement {
+	c := make([]GfElement, len(p.coefficients))
+	copy(c, p.coefficients)
+	return c
+}
+
+// the coefficient of x^i, which is 0 above the degree
+func (p Polynomial) Coefficient(i int) GfElement {
+	if i < 0 || i >= len(p.coefficients) {
+		return 0
+	}
+	return p.coefficients[i]
+}
+
+// the degree of the polynomial, or -1 for the zero polynomial
+func (p Polynomial) Degree() int {
+	return len(p.coefficients) - 1
+}
+
+func (p Polynomial) IsZero() bool {
+	return len(p.coefficients) == 0
+}
+
+// the coefficient of the highest power of x, or 0 for the zero polynomial
+func (p Polynomial) LeadingCoefficient() GfElement {
+	return p.Coefficient(p.Degree())
+}
+
+func (p Polynomial) Equal(q Polynomial) bool {
+	if len(p.coefficients) != len(q.coefficients) {
+		return false
+	}
+	for i := range p.coefficients {
+		if p.coefficients[i] != q.coefficients[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (p Polynomial) String() string {
+	if p.IsZero() {
+		return "0"
+	}
+
+	terms := make([]string, 0, len(p.coefficients))
+	for i := p.Degree(); i > -1; i-- {
+		c := p.coefficients[i]
+		switch {
+		case c == 0:
+			continue
+		case i == 0:
+			terms = append(terms, fmt.Sprintf("%d", c))
+		case i == 1:
+			terms = append(terms, fmt.Sprintf("%d*x", c))
+		default:
+			terms = append(terms, fmt.Sprintf("%d*x^%d", c, i))
+		}
+	}
+	return strings.Join(terms, " + ")
+}
+
+// evaluate the polynomial at x
+func (p Polynomial) Evaluate(x GfElement) GfElement {
+	return evaluatePolynomial(p.field, p.coefficients, x)
+}
+
+// add two polynomials over the same field
+func (p Polynomial) Add(q Polynomial) Polynomial {
+	n := max(len(p.coefficients), len(q.coefficients))
+	c := make([]GfElement, n)
+	for i := range c {
+		c[i] = p.field.Add(p.Coefficient(i), q.Coefficient(i))
+	}
+	return Polynomial{field: p.field, coefficients: c}.trim()
+}
+
+// subtract q from p
+// addition and subtraction are the same in GF(2^m)
+func (p Polynomial) Subtract(q Polynomial) Polynomial {
+	return p.Add(q)
+}
+
+// multiply every coefficient by a
+func (p Polynomial) Scale(a GfElement) Polynomial {
+	c := make([]GfElement, len(p.coefficients))
+	for i := range c {
+		c[i] = p.field.Multiply(p.coefficients[i], a)
+	}
+	return Polynomial{field: p.field, coefficients: c}.trim()
+}
+
+// multiply two polynomials over the same field
+func (p Polynomial) Multiply(q Polynomial) Polynomial {
+	if p.IsZero() || q.IsZero() {
+		return Polynomial{field: p.field}
+	}
+
+	c := make([]GfElement, len(p.coefficients)+len(q.coefficients)-1)
+	for i, a := range p.coefficients {
+		for j, b := range q.coefficients {
+			c[i+j] = p.field.Add(c[i+j], p.field.Multiply(a, b))
+		}
+	}
+	return Polynomial{field: p.field, coefficients: c}.trim()
+}
+
+// divide p by q, returning the quotient and a remainder of lower degree than q
+func (p Polynomial) DivMod(q Polynomial) (Polynomial, Polynomial, error) {
+	if q.IsZero() {
+		return Polynomial{}, Polynomial{}, ErrZeroPolynomial
+	}
+	if p.Degree() < q.Degree() {
+		return Polynomial{field: p.field}, p, nil
+	}
+
+	lead, err := p.field.Inverse(q.LeadingCoefficient())
+	if err != nil {
+		return Polynomial{}, Polynomial{}, err
+	}
+
+	remainder := p.Coefficients()
+	quotient := make([]GfElement, p.Degree()-q.Degree()+1)
+
+	// cancel the leading term of the remainder until its degree drops below q's
+	for d := p.Degree(); d >= q.Degree(); d-- {
+		c := p.field.Multiply(remainder[d], lead)
+		if c == 0 {
+			continue
+		}
+		shift := d - q.Degree()
+		quotient[shift] = c
+		for i, b := range q.coefficients {
+			remainder[shift+i] = p.field.Subtract(remainder[shift+i], p.field.Multiply(c, b))
+		}
+	}
+
+	return Polynomial{field: p.field, coefficients: quotient}.trim(),
+		Polynomial{field: p.field, coefficients: remainder}.trim(), nil
+}
+
+// the formal derivative
+// in characteristic 2, i*a is a for odd i and 0 for even i
+func (p Polynomial) Derivative() Polynomial {
+	if len(p.coefficients) < 2 {
+		return Polynomial{field: p.field}
+	}
+
+	c := make([]GfElement, len(p.coefficients)-1)
+	for i := 1; i < len(p.coefficients); i += 2 {
+		c[i-1] = p.coefficients[i]
+	}
+	return Polynomial{field: p.field, coefficients: c}.trim()
+}
+
+// scale the polynomial so its leading coefficient is 1
+// the zero polynomial is returned unchanged
+func (p Polynomial) Monic() Polynomial {
+	if p.IsZero() {
+		return p
+	}
+	// the leading coefficient is nonzero, so it always has an inverse
+	inverse, _ := p.field.Inverse(p.LeadingCoefficient())
+	return p.Scale(inverse)
+}
+
+// the monic greatest common divisor of p and q, or the zero polynomial if both are zero
+func (p Polynomial) GCD(q Polynomial) Polynomial {
+	a, b := p, q
+	for !b.IsZero() {
+		// b is nonzero, so the division can't fail
+		_, r, _ := a.DivMod(b)
+		a, b = b, r
+	}
+	return a.Monic()
+}
+
+// find the polynomial of lowest degree through the points (xs[j], ys[j]) using Lagrange interpolation
+func InterpolatePolynomial(field Gf2m, xs, ys []GfElement) (Polynomial, error) {
+	if len(xs) != len(ys) {
+		return Polynomial{}, ErrInconsistentLength
+	}
+
+	basis, err := lagrangeBasis(field, xs)
+	if err != nil {
+		return Polynomial{}, err
+	}
+
+	result := Polynomial{field: field}
+	for j := range basis {
+		result = result.Add(basis[j].Scale(ys[j]))
+	}
+
+	return result, nil
+}
+
+// the Lagrange basis polynomials for xs, where the jth is 1 at xs[j] and 0 at every other x
+func lagrangeBasis(field Gf2m, xs []GfElement) ([]Polynomial, error) {
+	basis := make([]Polynomial, len(xs))
+	for j := range xs {
+		numerator := NewPolynomial(field, 1)
+		denominator := GfElement(1)
+		for k := range xs {
+			if k == j {
+				continue
+			}
+			if xs[k] == xs[j] {
+				return nil, ErrDuplicateShare
+			}
+			numerator = numerator.Multiply(NewPolynomial(field, xs[k], 1))
+			denominator = field.Multiply(denominator, field.Subtract(xs[j], xs[k]))
+		}
+
+		scale, err := field.Inverse(denominator)
+		if err != nil {
+			return nil, err
+		}
+		basis[j] = numerator.Scale(scale)
+	}
+
+	return basis, nil
+}
@@ -0,0 +1,127 @@
+package shamir
+
+import (
+	"testing"
+)
+
+func TestPolynomialArithmetic(t *testing.T) {
+	field := NewField(0b10011)
+
+	// (x + 2)(x + 3) = x^2 + x + 6 in GF(16)
+	p := NewPolynomial(field, 2, 1)
+	q := NewPolynomial(field, 3, 1)
+	product := p.Multiply(q)
+	if want := NewPolynomial(field, 6, 1, 1); !product.Equal(want) {
+		t.Errorf("(x+2)(x+3)=%s, not %s", want, product)
+	}
+
+	if have, want := p.Add(q), NewPolynomial(field, 1); !have.Equal(want) {
+		t.Errorf("(x+2)+(x+3)=%s, not %s", want, have)
+	}
+
+	if have := p.Subtract(p); !have.IsZero() || have.Degree() != -1 {
+		t.Errorf("p-p should be zero, not %s", have)
+	}
+
+	if have := NewPolynomial(field, 1, 2, 0, 0); have.Degree() != 1 {
+		t.Errorf("trailing zero coefficients should be trimmed, degree is %d", have.Degree())
+	}
+
+	for x := GfElement(0); x < 16; x++ {
+		if have, want := product.Evaluate(x), field.Multiply(p.Evaluate(x), q.Evaluate(x)); have != want {
+			t.Errorf("product at %d should be %d, not %d", x, want, have)
+		}
+	}
+}
+
+func TestPolynomialDivMod(t *testing.T) {
+	field := NewField(0x11b)
+
+	a := NewPolynomial(field, 7, 200, 13, 91, 1, 45)
+	b := NewPolynomial(field, 3, 17, 99)
+
+	quotient, remainder, err := a.DivMod(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remainder.Degree() >= b.Degree() {
+		t.Errorf("remainder %s has degree at least %d", remainder, b.Degree())
+	}
+	if have := quotient.Multiply(b).Add(remainder); !have.Equal(a) {
+		t.Errorf("q*b+r=%s, not %s", have, a)
+	}
+
+	// dividing a polynomial of lower degree leaves it as the remainder
+	quotient, remainder, err = b.DivMod(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !quotient.IsZero() || !remainder.Equal(b) {
+		t.Errorf("b/a should be 0 remainder b, not %s remainder %s", quotient, remainder)
+	}
+
+	if _, _, err := a.DivMod(NewPolynomial(field)); err != ErrZeroPolynomial {
+		t.Errorf("division by zero should fail, got %v", err)
+	}
+}
+
+func TestPolynomialDerivative(t *testing.T) {
+	field := NewField(0x11b)
+
+	// d/dx (5 + 4x + 3x^2 + 2x^3) = 4 + 2*3x + 3*2x^2 = 4 + 2x^2 in characteristic 2
+	p := NewPolynomial(field, 5, 4, 3, 2)
+	if have, want := p.Derivative(), NewPolynomial(field, 4, 0, 2); !have.Equal(want) {
+		t.Errorf("derivative should be %s, not %s", want, have)
+	}
+
+	if have := NewPolynomial(field, 9).Derivative(); !have.IsZero() {
+		t.Errorf("derivative of a constant should be zero, not %s", have)
+	}
+}
+
+func TestPolynomialGCD(t *testing.T) {
+	field := NewField(0x11d)
+
+	common := NewPolynomial(field, 5, 1).Multiply(NewPolynomial(field, 9, 1))
+	p := common.Multiply(NewPolynomial(field, 33, 1)).Scale(7)
+	q := common.Multiply(NewPolynomial(field, 21, 4, 1))
+
+	if have := p.GCD(q); !have.Equal(common) {
+		t.Errorf("gcd should be %s, not %s", common, have)
+	}
+
+	if have := p.GCD(NewPolynomial(field)); !have.Equal(p.Monic()) {
+		t.Errorf("gcd(p, 0) should be monic p, not %s", have)
+	}
+
+	if have := NewPolynomial(field, 5, 1).GCD(NewPolynomial(field, 9, 1)); !have.Equal(NewPolynomial(field, 1)) {
+		t.Errorf("coprime polynomials should have gcd 1, not %s", have)
+	}
+}
+
+func TestInterpolatePolynomial(t *testing.T) {
+	field := NewField(0x11b)
+
+	p := NewPolynomial(field, 42, 7, 0, 190, 3)
+	xs := []GfElement{1, 2, 3, 4, 5, 6}
+	ys := make([]GfElement, len(xs))
+	for i, x := range xs {
+		ys[i] = p.Evaluate(x)
+	}
+
+	have, err := InterpolatePolynomial(field, xs, ys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !have.Equal(p) {
+		t.Errorf("interpolated %s, not %s", have, p)
+	}
+
+	if _, err := InterpolatePolynomial(field, []GfElement{1, 1}, []GfElement{2, 3}); err != ErrDuplicateShare {
+		t.Errorf("duplicate x coordinates should fail, got %v", err)
+	}
+
+	if _, err := InterpolatePolynomial(field, []GfElement{1, 2}, []GfElement{2}); err != ErrInconsistentLength {
+		t.Errorf("mismatched lengths should fail, got %v", err)
+	}
+}
@@ -0,0 +1,264 @@
+package shamir
+
+import (
+	"crypto/elliptic"
+	crand "crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+)
+
+// GF(p) for a large prime p, so that secrets such as elliptic curve private keys can be shared as a single big integer
+// shares over GF(p) look like shamir-<id>-<field>-<x>-<y>, where the field is a name such as p256 or p<hex modulus>,
+// and y is a big-endian integer padded to the size of p
+
+var ErrNotPrime error = errors.New("modulus of a prime field must be an odd prime")
+var ErrUnknownPrimeField error = errors.New("unknown prime field; use p256, secp256k1, or p followed by a prime in hex")
+var ErrSecretOutOfRange error = errors.New("secret must be a big-endian integer smaller than the field's modulus")
+
+// the group orders of common elliptic curves, whose private keys are integers modulo the order
+var namedPrimeFields = map[string]*big.Int{
+	"p256":      elliptic.P256().Params().N,
+	"secp256k1": mustParseHex("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141"),
+}
+
+func mustParseHex(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("invalid hex constant " + s)
+	}
+	return n
+}
+
+type Fp struct {
+	p    *big.Int
+	name string // identifies the field in share labels
+	size int    // bytes in an encoded element
+}
+
+// NewPrimeField constructs GF(p), checking that p is (very probably) prime
+func NewPrimeField(p *big.Int) (Fp, error) {
+	if p.Bit(0) != 1 || p.Cmp(big.NewInt(3)) < 0 || !p.ProbablyPrime(32) {
+		return Fp{}, ErrNotPrime
+	}
+
+	field := Fp{
+		p:    new(big.Int).Set(p),
+		name: fmt.Sprintf("p%x", p),
+		size: (p.BitLen() + 7) / 8,
+	}
+
+	for name, modulus := range namedPrimeFields {
+		if modulus.Cmp(p) == 0 {
+			field.name = name
+		}
+	}
+
+	return field, nil
+}
+
+// PrimeFieldByName constructs the prime field with the name used in share labels, such as p256 or p<hex modulus>
+func PrimeFieldByName(name string) (Fp, error) {
+	if p, ok := namedPrimeFields[name]; ok {
+		return NewPrimeField(p)
+	}
+
+	hex, ok := strings.CutPrefix(name, "p")
+	if !ok {
+		return Fp{}, ErrUnknownPrimeField
+	}
+	p, ok := new(big.Int).SetString(hex, 16)
+	if !ok {
+		return Fp{}, ErrUnknownPrimeField
+	}
+	return NewPrimeField(p)
+}
+
+func (field Fp) String() string {
+	return fmt.Sprintf("GF(p) with p = 0x%x", field.p)
+}
+
+func (field Fp) GetModulus() *big.Int {
+	return new(big.Int).Set(field.p)
+}
+
+// the name of the field in share labels
+func (field Fp) GetName() string {
+	return field.name
+}
+
+// the number of bytes in an encoded element
+func (field Fp) GetElementSize() int {
+	return field.size
+}
+
+// add two elements in the field
+func (field Fp) Add(a, b *big.Int) *big.Int {
+	c := new(big.Int).Add(a, b)
+	return c.Mod(c, field.p)
+}
+
+// subtract b from a
+func (field Fp) Subtract(a, b *big.Int) *big.Int {
+	c := new(big.Int).Sub(a, b)
+	return c.Mod(c, field.p)
+}
+
+// multiply two elements in the field
+func (field Fp) Multiply(a, b *big.Int) *big.Int {
+	c := new(big.Int).Mul(a, b)
+	return c.Mod(c, field.p)
+}
+
+// compute the multiplicative inverse of a
+func (field Fp) Inverse(a *big.Int) (*big.Int, error) {
+	if new(big.Int).Mod(a, field.p).Sign() == 0 {
+		return nil, ErrNotInvertible
+	}
+	return new(big.Int).ModInverse(a, field.p), nil
+}
+
+// divide a by b
+func (field Fp) Divide(a, b *big.Int) (*big.Int, error) {
+	if new(big.Int).Mod(b, field.p).Sign() == 0 {
+		return nil, errors.New("division by zero")
+	}
+
+	inverse, err := field.Inverse(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return field.Multiply(a, inverse), nil
+}
+
+// the element i mod p
+func (field Fp) Element(i int64) *big.Int {
+	return new(big.Int).Mod(big.NewInt(i), field.p)
+}
+
+// a uniformly random element from a cryptographically secure source
+func (field Fp) Random() (*big.Int, error) {
+	return field.RandomFrom(crand.Reader)
+}
+
+// a uniformly random element drawn from r
+func (field Fp) RandomFrom(r io.Reader) (*big.Int, error) {
+	return crand.Int(r, field.p)
+}
+
+// encode an element as a big-endian integer padded to the size of p
+func (field Fp) Encode(a *big.Int) []byte {
+	return a.FillBytes(make([]byte, field.size))
+}
+
+// decode an element encoded with Encode, or any big-endian integer smaller than p
+func (field Fp) Decode(b []byte) (*big.Int, error) {
+	a := new(big.Int).SetBytes(b)
+	if len(b) > field.size || a.Cmp(field.p) >= 0 {
+		return nil, ErrSecretOutOfRange
+	}
+	return a, nil
+}
+
+// overwrite the words of a big integer, then set it to zero
+func wipeInt(a *big.Int) {
+	words := a.Bits()
+	for i := range words {
+		words[i] = 0
+	}
+	a.SetInt64(0)
+}
+
+// WithPrimeField has NewShamirSecret share the secret as a single element of the prime field instead of byte by byte over GF(2^m).
+// The secret is read as a big-endian integer smaller than p, and is recovered padded to the size of p.
+// The primitive polynomial given to NewShamirSecret is ignored.
+func WithPrimeField(field Fp) Option {
+	return func(o *options) {
+		o.primeField = &field
+	}
+}
+
+// compute the y coordinates of shares of the secret over GF(p), drawing coefficients from r
+func splitPrimeSecret(field Fp, secret []byte, xs []*big.Int, threshold int, r io.Reader) ([][]byte, error) {
+	constant, err := field.Decode(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	// the coefficients reveal the secret, so they are wiped once the shares are computed
+	p, err := randomPolynomial(field, constant, threshold-1, r)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, a := range p {
+			wipeInt(a)
+		}
+	}()
+
+	ys := make([][]byte, len(xs))
+	for i, x := range xs {
+		ys[i] = field.Encode(evaluatePolynomial(field, p, x))
+	}
+
+	return ys, nil
+}
+
+// recover the secret shared over GF(p) into memory provided by alloc
+func recoverPrimeSecret(shares []Share, o options, alloc func(n int) ([]byte, error)) ([]byte, error) {
+	field, err := PrimeFieldByName(shares[0].group)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := interpolatePrime(field, shares, field.Element(0))
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := alloc(field.size)
+	if err != nil {
+		return nil, err
+	}
+	value.FillBytes(secret)
+	wipeInt(value)
+
+	if o.commitment != "" {
+		if err := VerifyRecovered(secret, o.commitment); err != nil {
+			Wipe(secret)
+			return nil, err
+		}
+	}
+
+	return secret, nil
+}
+
+// evaluate the polynomial through the shares over GF(p) at x
+func interpolatePrime(field Fp, shares []Share, x *big.Int) (*big.Int, error) {
+	xs := make([]*big.Int, len(shares))
+	ys := make([]*big.Int, len(shares))
+	defer func() {
+		for _, y := range ys {
+			if y != nil {
+				wipeInt(y)
+			}
+		}
+	}()
+
+	for i, share := range shares {
+		xs[i] = field.Element(int64(share.x))
+
+		b := share.yBytes()
+		var err error
+		ys[i], err = field.Decode(b)
+		Wipe(b)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return lagrangeInterpolate(field, xs, ys, x)
+}
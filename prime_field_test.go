@@ -0,0 +1,130 @@
+package shamir
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestPrimeField(t *testing.T) {
+	field, err := NewPrimeField(big.NewInt(101))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if have, want := field.Add(big.NewInt(60), big.NewInt(50)), big.NewInt(9); have.Cmp(want) != 0 {
+		t.Errorf("60+50=%d, not %d", want, have)
+	}
+	if have, want := field.Subtract(big.NewInt(3), big.NewInt(5)), big.NewInt(99); have.Cmp(want) != 0 {
+		t.Errorf("3-5=%d, not %d", want, have)
+	}
+	if have, want := field.Multiply(big.NewInt(20), big.NewInt(30)), big.NewInt(95); have.Cmp(want) != 0 {
+		t.Errorf("20*30=%d, not %d", want, have)
+	}
+
+	for a := int64(1); a < 101; a++ {
+		q, err := field.Divide(big.NewInt(1), big.NewInt(a))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if field.Multiply(q, big.NewInt(a)).Cmp(big.NewInt(1)) != 0 {
+			t.Errorf("1/%d=%d is not an inverse", a, q)
+		}
+	}
+	if _, err := field.Divide(big.NewInt(1), big.NewInt(101)); err == nil {
+		t.Error("division by zero succeeded")
+	}
+
+	for _, p := range []int64{1, 2, 91, 100} {
+		if _, err := NewPrimeField(big.NewInt(p)); err != ErrNotPrime {
+			t.Errorf("%d: have %v, want %v", p, err, ErrNotPrime)
+		}
+	}
+
+	if field.GetName() != "p65" {
+		t.Errorf("have name %s, want p65", field.GetName())
+	}
+	for _, name := range []string{"p65", "p256", "secp256k1"} {
+		named, err := PrimeFieldByName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if named.GetName() != name {
+			t.Errorf("have name %s, want %s", named.GetName(), name)
+		}
+	}
+	if _, err := PrimeFieldByName("p64"); err != ErrNotPrime {
+		t.Errorf("have %v, want %v", err, ErrNotPrime)
+	}
+	if _, err := PrimeFieldByName("q65"); err != ErrUnknownPrimeField {
+		t.Errorf("have %v, want %v", err, ErrUnknownPrimeField)
+	}
+}
+
+func TestShamirPrimeField(t *testing.T) {
+	// share a P-256 private key
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := key.D.FillBytes(make([]byte, 32))
+
+	field, err := PrimeFieldByName("p256")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewShamirSecret(0, 5, 3, secret, WithPrimeField(field))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if label := s.GetShares()[0].ShareLabel(); !strings.HasSuffix(label, "-p256-1") {
+		t.Fatalf("unexpected label %s", label)
+	}
+
+	shares, err := NewSharesFromString(s.ShareString(4) + " " + s.ShareString(0) + " " + s.ShareString(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	recovered, err := RecoverSecret(shares, WithCommitment(s.GetCommitment()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(recovered, secret) {
+		t.Fatalf("have %x, want %x", recovered, secret)
+	}
+
+	// two shares reveal nothing
+	recovered, err = RecoverSecret(shares[:2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(recovered, secret) {
+		t.Fatal("recovered the secret from too few shares")
+	}
+
+	// short secrets are recovered padded to the size of the field
+	mersenne, err := NewPrimeField(new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err = NewShamirSecret(0, 3, 2, []byte{1, 2, 3}, WithPrimeField(mersenne))
+	if err != nil {
+		t.Fatal(err)
+	}
+	recovered, err = RecoverSecret(s.GetShares()[1:], WithCommitment(s.GetCommitment()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := append(make([]byte, 13), 1, 2, 3); !bytes.Equal(recovered, want) {
+		t.Fatalf("have %x, want %x", recovered, want)
+	}
+
+	if _, err := NewShamirSecret(0, 3, 2, bytes.Repeat([]byte{0xff}, 32), WithPrimeField(field)); err != ErrSecretOutOfRange {
+		t.Errorf("have %v, want %v", err, ErrSecretOutOfRange)
+	}
+}
@@ -0,0 +1,108 @@
+package shamir
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// a SecretBuffer holds a secret outside of the garbage-collected heap where possible,
+// locked into memory so it is never swapped to disk, and wiped when it is closed
+type SecretBuffer struct {
+	data   []byte
+	locked bool
+}
+
+// NewSecretBuffer allocates a zeroed buffer of the given size.
+// If the memory can't be locked (for example, because RLIMIT_MEMLOCK is too low), the buffer is still usable but Locked reports false.
+func NewSecretBuffer(size int) (*SecretBuffer, error) {
+	data, locked, err := allocLocked(size)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &SecretBuffer{data: data, locked: locked}
+	runtime.SetFinalizer(buf, (*SecretBuffer).Close)
+	return buf, nil
+}
+
+// NewSecretBufferFrom copies data into a new SecretBuffer and wipes the original
+func NewSecretBufferFrom(data []byte) (*SecretBuffer, error) {
+	buf, err := NewSecretBuffer(len(data))
+	if err != nil {
+		return nil, err
+	}
+
+	copy(buf.data, data)
+	Wipe(data)
+	return buf, nil
+}
+
+// copy data into a new SecretBuffer, leaving the original alone
+func lockedCopy(data []byte) (*SecretBuffer, error) {
+	buf, err := NewSecretBuffer(len(data))
+	if err != nil {
+		return nil, err
+	}
+	copy(buf.data, data)
+	return buf, nil
+}
+
+// allocate n zeroed field elements in a SecretBuffer, for values such as coefficients that would reveal the secret
+// locked memory is page aligned, and the heap fallback is a multiple of 8 bytes so it is 8-byte aligned, so either can hold elements
+func lockedElements(n int) ([]GfElement, *SecretBuffer, error) {
+	buf, err := NewSecretBuffer(n * int(unsafe.Sizeof(GfElement(0))))
+	if err != nil {
+		return nil, nil, err
+	}
+	if n == 0 {
+		return []GfElement{}, buf, nil
+	}
+	return unsafe.Slice((*GfElement)(unsafe.Pointer(&buf.data[0])), n), buf, nil
+}
+
+// the contents of the buffer, which are only valid until it is closed
+func (buf *SecretBuffer) Bytes() []byte {
+	return buf.data
+}
+
+// whether the buffer is locked into memory
+func (buf *SecretBuffer) Locked() bool {
+	return buf.locked
+}
+
+// Close wipes and releases the buffer. It is safe to call more than once.
+func (buf *SecretBuffer) Close() error {
+	if buf.data == nil {
+		return nil
+	}
+
+	Wipe(buf.data)
+	err := freeLocked(buf.data, buf.locked)
+	buf.data = nil
+	buf.locked = false
+	runtime.SetFinalizer(buf, nil)
+	return err
+}
+
+// Wipe overwrites b with zeros
+func Wipe(b []byte) {
+	clear(b)
+	runtime.KeepAlive(b)
+}
+
+func wipeElements(e []GfElement) {
+	clear(e)
+	runtime.KeepAlive(e)
+}
+
+// Wipe overwrites the share's y values with zeros once it is no longer needed
+func (share Share) Wipe() {
+	wipeElements(share.y)
+}
+
+// Wipe overwrites every share with zeros once they have been distributed
+func (shamir *Shamir) Wipe() {
+	for _, share := range shamir.shares {
+		share.Wipe()
+	}
+}
@@ -0,0 +1,36 @@
+//go:build linux
+
+package shamir
+
+import "golang.org/x/sys/unix"
+
+// allocate anonymous memory outside the Go heap and lock it so it isn't swapped to disk
+func allocLocked(size int) ([]byte, bool, error) {
+	if size == 0 {
+		return []byte{}, false, nil
+	}
+
+	data, err := unix.Mmap(-1, 0, size, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_ANON|unix.MAP_PRIVATE)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// keep the secret out of core dumps, even if they are enabled
+	_ = unix.Madvise(data, unix.MADV_DONTDUMP)
+
+	locked := unix.Mlock(data) == nil
+	return data, locked, nil
+}
+
+func freeLocked(data []byte, locked bool) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	if locked {
+		if err := unix.Munlock(data); err != nil {
+			return err
+		}
+	}
+	return unix.Munmap(data)
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package shamir
+
+// memory locking is only implemented on Linux; elsewhere, buffers are still wiped on Close
+func allocLocked(size int) ([]byte, bool, error) {
+	return make([]byte, size), false, nil
+}
+
+func freeLocked(data []byte, locked bool) error {
+	return nil
+}
@@ -0,0 +1,98 @@
+package shamir
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSecretBuffer(t *testing.T) {
+	secret := []byte("kept out of swap")
+
+	s, err := NewShamirSecret(0x11d, 3, 2, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := RecoverSecretBuffer(s.GetShares()[1:], WithCommitment(s.GetCommitment()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+	if !bytes.Equal(data, secret) {
+		t.Fatalf("have %q, want %q", data, secret)
+	}
+
+	if err := buf.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Bytes() != nil {
+		t.Error("closed buffer still holds data")
+	}
+	if err := buf.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := RecoverSecretBuffer(s.GetShares()[:1], WithCommitment(s.GetCommitment())); err != ErrCommitmentMismatch {
+		t.Errorf("have %v, want %v", err, ErrCommitmentMismatch)
+	}
+
+	s.Wipe()
+	for _, share := range s.GetShares() {
+		if share.GetYString() != "AAAAAAAAAAAAAAAAAAAAAA" {
+			t.Errorf("share %s was not wiped", share)
+		}
+	}
+}
+
+func TestNewSecretBufferFrom(t *testing.T) {
+	data := []byte("copied then wiped")
+
+	buf, err := NewSecretBufferFrom(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer buf.Close()
+
+	if !bytes.Equal(buf.Bytes(), []byte("copied then wiped")) {
+		t.Fatalf("have %q", buf.Bytes())
+	}
+	if !bytes.Equal(data, make([]byte, len(data))) {
+		t.Error("original was not wiped")
+	}
+}
+
+func TestLockedElements(t *testing.T) {
+	for _, n := range []int{0, 1, 3, 1000} {
+		elements, buf, err := lockedElements(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(elements) != n {
+			t.Errorf("have %d elements, want %d", len(elements), n)
+		}
+		for i := range elements {
+			elements[i] = GfElement(i)
+		}
+
+		if err := buf.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// recovering through locked buffers gives the same secret as before
+	for _, opts := range [][]Option{nil, {WithScheme(SchemeAont)}, {WithScheme(SchemeIda)}} {
+		secret := []byte("held in locked memory")
+		s, err := NewShamirSecret(0x11d, 4, 3, secret, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		recovered, err := RecoverSecret(s.GetShares()[1:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(recovered, secret) {
+			t.Errorf("have %q, want %q", recovered, secret)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package shamir
+
+import (
+	crand "crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/chacha20"
+	"golang.org/x/crypto/hkdf"
+)
+
+// in deterministic mode, everything NewShamirSecret would choose at random is drawn from a ChaCha20 keystream instead
+// the key is HKDF-SHA256 with the secret as input keying material, the seed as salt, and the parameters as info,
+// so the same secret, seed, and parameters always give the same secret ID, commitment, and shares
+// draws are made in order: the secret ID, the commitment's salt, random x coordinates, then the coefficients
+// anyone with the seed, k-1 shares, and a guess at the secret can check the guess, so the seed must be kept as safe as the secret
+
+const seedInfo string = "shamir-seed-v1"
+const MinSeedLen int = 16
+
+var ErrShortSeed error = errors.New("seed must be at least 16 bytes")
+
+// WithSeed derives the secret ID, commitment, and shares from the seed and the secret, so they can be regenerated
+func WithSeed(seed []byte) Option {
+	return func(o *options) {
+		o.seed = append([]byte{}, seed...)
+	}
+}
+
+// an endless stream of pseudorandom bytes
+type seededReader struct {
+	stream *chacha20.Cipher
+}
+
+func (r *seededReader) Read(p []byte) (int, error) {
+	clear(p)
+	r.stream.XORKeyStream(p, p)
+	return len(p), nil
+}
+
+func newSeededReader(seed, secret []byte, info string) (io.Reader, error) {
+	if len(seed) < MinSeedLen {
+		return nil, ErrShortSeed
+	}
+
+	key := make([]byte, chacha20.KeySize)
+	defer Wipe(key)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, seed, []byte(info)), key); err != nil {
+		return nil, err
+	}
+
+	stream, err := chacha20.NewUnauthenticatedCipher(key, make([]byte, chacha20.NonceSize))
+	if err != nil {
+		return nil, err
+	}
+
+	return &seededReader{stream: stream}, nil
+}
+
+// the source of randomness for splitting the secret, which is seeded in deterministic mode
+// field is the field's name in share labels, along with any other parameters the label records
+func (o options) randomness(secret []byte, field string, nshares, threshold int) (io.Reader, error) {
+	if o.seed == nil {
+		return crand.Reader, nil
+	}
+
+	info := fmt.Sprintf("%s:%s:%s:%d:%d", seedInfo, field, o.scheme, nshares, threshold)
+	return newSeededReader(o.seed, secret, info)
+}
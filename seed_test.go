@@ -0,0 +1,156 @@
+package shamir
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+// a test vector for deterministic splitting, as published in testdata/seed_vectors.json
+type seedVector struct {
+	Description string   `json:"description"`
+	Primitive   int      `json:"primitive,omitempty"`
+	PrimeField  string   `json:"prime_field,omitempty"`
+	Scheme      string   `json:"scheme,omitempty"`
+	N           int      `json:"n"`
+	K           int      `json:"k"`
+	Xs          []int    `json:"xs,omitempty"`
+	RandomXs    bool     `json:"random_xs,omitempty"`
+	Seed        string   `json:"seed"`
+	Secret      string   `json:"secret"`
+	Id          string   `json:"id"`
+	Commitment  string   `json:"commitment"`
+	Shares      []string `json:"shares"`
+}
+
+func (v seedVector) split(t *testing.T) *Shamir {
+	seed, err := hex.DecodeString(v.Seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret, err := hex.DecodeString(v.Secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := []Option{WithSeed(seed)}
+	if v.PrimeField != "" {
+		field, err := PrimeFieldByName(v.PrimeField)
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts = append(opts, WithPrimeField(field))
+	}
+	if v.Scheme != "" {
+		opts = append(opts, WithScheme(Scheme(v.Scheme)))
+	}
+	if len(v.Xs) > 0 {
+		xs := make([]GfElement, len(v.Xs))
+		for i, x := range v.Xs {
+			xs[i] = GfElement(x)
+		}
+		opts = append(opts, WithXs(xs...))
+	}
+	if v.RandomXs {
+		opts = append(opts, WithRandomXs())
+	}
+
+	s, err := NewShamirSecret(v.Primitive, v.N, v.K, secret, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSeedVectors(t *testing.T) {
+	data, err := os.ReadFile("testdata/seed_vectors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var vectors []seedVector
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range vectors {
+		t.Run(v.Description, func(t *testing.T) {
+			s := v.split(t)
+
+			if s.GetId() != v.Id {
+				t.Errorf("secret ID is %s, not %s", s.GetId(), v.Id)
+			}
+			if s.GetCommitment() != v.Commitment {
+				t.Errorf("commitment is %s, not %s", s.GetCommitment(), v.Commitment)
+			}
+			if len(s.GetShares()) != len(v.Shares) {
+				t.Fatalf("%d shares, not %d", len(s.GetShares()), len(v.Shares))
+			}
+			for i, share := range s.GetShares() {
+				if share.String() != v.Shares[i] {
+					t.Errorf("share %d is %s, not %s", i, share, v.Shares[i])
+				}
+			}
+
+			// the published shares recover the secret
+			shares, err := NewSharesFromString(strings.Join(v.Shares[len(v.Shares)-v.K:], "\n"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			recovered, err := RecoverSecret(shares, WithCommitment(v.Commitment))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(recovered) != v.Secret {
+				t.Errorf("recovered %x, not %s", recovered, v.Secret)
+			}
+		})
+	}
+}
+
+func TestSeedDeterminism(t *testing.T) {
+	seed := bytes.Repeat([]byte{7}, 32)
+	secret := []byte("regenerate me")
+
+	a, err := NewShamirSecret(0x11d, 5, 3, secret, WithSeed(seed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewShamirSecret(0x11d, 5, 3, secret, WithSeed(seed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.String() != b.String() {
+		t.Errorf("the same seed and secret gave different shares:\n%s\n%s", a, b)
+	}
+
+	// a different secret, seed, or threshold gives unrelated shares
+	others := make([]*Shamir, 0)
+	for _, opts := range []struct {
+		secret []byte
+		seed   []byte
+		k      int
+	}{
+		{[]byte("regenerate mE"), seed, 3},
+		{secret, bytes.Repeat([]byte{8}, 32), 3},
+		{secret, seed, 2},
+	} {
+		s, err := NewShamirSecret(0x11d, 5, opts.k, opts.secret, WithSeed(opts.seed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		others = append(others, s)
+	}
+	for _, other := range others {
+		if other.GetId() == a.GetId() || other.ShareString(0) == a.ShareString(0) {
+			t.Errorf("changing the inputs should change the shares:\n%s\n%s", a, other)
+		}
+	}
+
+	if _, err := NewShamirSecret(0x11d, 5, 3, secret, WithSeed([]byte("short"))); err != ErrShortSeed {
+		t.Errorf("a short seed should fail, got %v", err)
+	}
+}
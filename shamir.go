@@ -1,27 +1,34 @@
 package shamir
 
 import (
-	crand "crypto/rand"
 	"encoding/base32"
 	"errors"
 	"fmt"
-	"math/rand/v2"
+	"io"
+	"math/big"
 )
 
 var ErrThresholdTooLarge error = errors.New("threshold cannot exceed number of shares")
 var ErrNonPrimitivePolynomial error = errors.New("supplied polynomial cannot be primitive")
+var ErrReduciblePolynomial error = errors.New("supplied polynomial is reducible, so it doesn't define a field")
+var ErrUnsupportedField error = errors.New("shares store one byte per y value, so the field must be GF(2^8)")
 var ErrMismatchedSecretID error = errors.New("secret ID's don't match")
 var ErrInconsistentLength error = errors.New("length of shares is inconsistent")
 var ErrDuplicateShare error = errors.New("duplicate shares provided")
+var ErrMismatchedPolynomial error = errors.New("shares use different primitive polynomials or groups")
+var ErrMismatchedThreshold error = errors.New("shares record different thresholds")
+var ErrNoShares error = errors.New("no shares provided")
 
 type Shamir struct {
-	id     string  // unique identifier to ensure shares were derived from same secret
-	field  Gf2m    // field over which to operate
-	shares []Share // individual shares to distribute
+	id         string  // unique identifier to ensure shares were derived from same secret
+	threshold  int     // number of shares needed to reconstruct the secret
+	commitment string  // salted hash of the secret
+	shares     []Share // individual shares to distribute
 }
 
 func (shamir Shamir) String() string {
 	s := fmt.Sprintf("Secret %s\n", shamir.id)
+	s += fmt.Sprintf("Commitment %s\n", shamir.commitment)
 	s += "Shares:\n"
 	for n := range shamir.shares {
 		s += fmt.Sprintf("  %s\n", shamir.ShareString(n))
@@ -33,131 +40,369 @@ func (shamir Shamir) GetId() string {
 	return shamir.id
 }
 
+// salted hash of the secret, to publish so the reconstructed secret can be checked
+func (shamir Shamir) GetCommitment() string {
+	return shamir.commitment
+}
+
+func (shamir Shamir) GetThreshold() int {
+	return shamir.threshold
+}
+
 func (shamir Shamir) ShareString(n int) string {
-	return fmt.Sprintf("%s-%s", shamir.shares[n].ShareLabel(), shamir.shares[n].GetYString())
+	return shamir.shares[n].String()
 }
 
 func (shamir Shamir) GetShares() []Share {
 	return shamir.shares
 }
 
-func NewShamirSecret(primitivePoly int, nshares int, threshold int, secret []byte) (*Shamir, error) {
+func NewShamirSecret(primitivePoly int, nshares int, threshold int, secret []byte, opts ...Option) (*Shamir, error) {
+	o := newOptions(opts)
 
 	// input validation
 	if threshold > nshares {
 		return nil, ErrThresholdTooLarge
 	}
-	if (primitivePoly & 0b1) != 1 {
+	if o.primeField == nil && (primitivePoly&0b1) != 1 {
 		return nil, ErrNonPrimitivePolynomial
 	}
-	// TODO better checking that polynomials are actually primitive
+	if o.primeField == nil && !IsIrreducible(primitivePoly) {
+		return nil, ErrReduciblePolynomial
+	}
+	if o.primeField == nil && ComputeDegree(primitivePoly) != 8 {
+		return nil, ErrUnsupportedField
+	}
+	if o.primeField != nil && o.scheme != SchemeShamir {
+		return nil, ErrUnsupportedGroup
+	}
+
+	// work on a locked copy of the secret, which is wiped when the shares are made
+	work, err := lockedCopy(secret)
+	if err != nil {
+		return nil, err
+	}
+	defer work.Close()
+	secret = work.Bytes()
+
+	// secrets over GF(p) are recovered padded to the size of p, so the commitment is to the padded secret
+	fieldName := fmt.Sprintf("%x", primitivePoly)
+	if o.primeField != nil {
+		value, err := o.primeField.Decode(secret)
+		if err != nil {
+			return nil, err
+		}
+		normalized, err := NewSecretBufferFrom(o.primeField.Encode(value))
+		wipeInt(value)
+		if err != nil {
+			return nil, err
+		}
+		defer normalized.Close()
+		secret = normalized.Bytes()
+		fieldName = o.primeField.GetName()
+	}
+
+	r, err := o.randomness(secret, fieldName, nshares, threshold)
+	if err != nil {
+		return nil, err
+	}
 
 	// generate random ID for secret shares
 	idbytes := make([]byte, 5)
-	if _, err := crand.Read(idbytes); err != nil {
+	if _, err := io.ReadFull(r, idbytes); err != nil {
+		return nil, err
+	}
+
+	commitment, err := newCommitment(r, secret)
+	if err != nil {
 		return nil, err
 	}
 
 	// initialize the data needed for Shamir's secret sharing scheme
 	shamir := &Shamir{
-		id:     base32.StdEncoding.EncodeToString(idbytes),
-		field:  NewField(primitivePoly),
-		shares: make([]Share, nshares),
+		commitment: commitment,
+		id:         base32.StdEncoding.EncodeToString(idbytes),
+		threshold:  threshold,
+		shares:     make([]Share, nshares),
+	}
+
+	// x coordinates must be elements of the field
+	var size *big.Int
+	if o.primeField != nil {
+		size = o.primeField.GetModulus()
+	} else {
+		size = big.NewInt(1 << ComputeDegree(primitivePoly))
+	}
+	xs, err := o.shareXs(nshares, size, r)
+	if err != nil {
+		return nil, err
 	}
 
 	// initialize each individual share
 	for i := range shamir.shares {
 		shamir.shares[i].secret_id = shamir.id
 		shamir.shares[i].primitivePoly = int64(primitivePoly)
-		shamir.shares[i].x = GfElement(i + 1)
+		shamir.shares[i].threshold = threshold
+		shamir.shares[i].x = xs[i]
+	}
+
+	if o.primeField != nil {
+		if err := shamir.splitOverPrimeField(*o.primeField, secret, r); err != nil {
+			return nil, err
+		}
+	} else if o.scheme != SchemeShamir {
+		if _, err := ParseScheme(string(o.scheme)); err != nil {
+			return nil, err
+		}
+		if err := shamir.splitDispersed(NewField(primitivePoly), o.scheme, secret, r); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := shamir.splitOverGf2m(NewField(primitivePoly), secret, r); err != nil {
+			return nil, err
+		}
+	}
+
+	if o.dealerKey != nil {
+		for i := range shamir.shares {
+			if err := shamir.shares[i].Sign(o.dealerKey); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return shamir, nil
+}
+
+// share each byte of the secret over GF(2^m), drawing coefficients from r
+func (shamir *Shamir) splitOverGf2m(field Gf2m, secret []byte, r io.Reader) error {
+	for i := range shamir.shares {
 		shamir.shares[i].y = make([]GfElement, len(secret))
 	}
 
+	// the coefficients reveal the secret, so they are locked and wiped once the shares are computed
+	p, pBuf, err := lockedElements(shamir.threshold)
+	if err != nil {
+		return err
+	}
+	defer pBuf.Close()
+
 	// choose new polynomials for each byte in secret
 	for i := 0; i < len(secret); i++ {
 
-		// choose random polynomial
-		p := make([]GfElement, threshold)
-		for i := range p {
-			p[i] = GfElement(rand.IntN(shamir.field.GetNelements()))
-		}
-
-		// set constant term to be secret
+		// choose random polynomial with the secret as its constant term
 		p[0] = GfElement(secret[i])
+		for j := 1; j < len(p); j++ {
+			var err error
+			p[j], err = field.RandomFrom(r)
+			if err != nil {
+				return err
+			}
+		}
 
 		// compute value of polynomial for each of the shares
 		for _, share := range shamir.shares {
-			share.y[i] = shamir.field.EvaluatePolynomial(p, share.x)
+			share.y[i] = field.EvaluatePolynomial(p, share.x)
 		}
 	}
 
-	return shamir, nil
+	return nil
 }
 
-func RecoverSecret(shares []Share) ([]byte, error) {
+// share the secret as a single element of GF(p), drawing coefficients from r
+func (shamir *Shamir) splitOverPrimeField(field Fp, secret []byte, r io.Reader) error {
+	xs := make([]*big.Int, len(shamir.shares))
+	for i, share := range shamir.shares {
+		xs[i] = field.Element(int64(share.x))
+	}
 
-	// check that shares all have same id
-	var secret_id string
-	for i := range shares {
-		if i == 0 {
-			secret_id = shares[0].secret_id
-		} else {
-			if shares[i].secret_id != secret_id {
-				return nil, ErrMismatchedSecretID
-			}
+	ys, err := splitPrimeSecret(field, secret, xs, shamir.threshold, r)
+	if err != nil {
+		return err
+	}
+
+	for i := range shamir.shares {
+		shamir.shares[i].primitivePoly = 0
+		shamir.shares[i].group = field.GetName()
+		shamir.shares[i].y = make([]GfElement, len(ys[i]))
+		for j, b := range ys[i] {
+			shamir.shares[i].y[j] = GfElement(b)
 		}
 	}
 
-	// check that shares are all same length
-	len_secret := len(shares[0].y)
-	for _, share := range shares {
-		if len(share.y) != len_secret {
-			return nil, ErrInconsistentLength
+	return nil
+}
+
+// RecoverSecret recovers the secret through a locked buffer, returning a copy on the heap that the caller should Wipe
+// use RecoverSecretBuffer to keep the secret itself out of the heap
+func RecoverSecret(shares []Share, opts ...Option) ([]byte, error) {
+	buf, err := RecoverSecretBuffer(shares, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer buf.Close()
+
+	secret := make([]byte, len(buf.Bytes()))
+	copy(secret, buf.Bytes())
+	return secret, nil
+}
+
+// RecoverSecretBuffer recovers the secret into a locked buffer, which the caller must close to wipe the secret
+func RecoverSecretBuffer(shares []Share, opts ...Option) (*SecretBuffer, error) {
+	var buf *SecretBuffer
+
+	_, err := recoverSecret(shares, newOptions(opts), func(n int) ([]byte, error) {
+		var err error
+		buf, err = NewSecretBuffer(n)
+		if err != nil {
+			return nil, err
 		}
+		return buf.Bytes(), nil
+	})
+	if err != nil {
+		if buf != nil {
+			buf.Close()
+		}
+		return nil, err
 	}
 
-	// initialize data
-	field := NewField(int(shares[0].GetPrimitivePoly()))
-	n_shares := len(shares)
-	secret := make([]byte, len_secret)
+	return buf, nil
+}
 
-	x := make([]GfElement, n_shares)
+// ValidateShares checks that the shares could all belong to the same secret, without reconstructing it
+func ValidateShares(shares []Share) error {
+	if len(shares) == 0 {
+		return ErrNoShares
+	}
+
+	threshold := 0
 	existingxs := make(map[GfElement]any, 0)
-	for s, share := range shares {
+	for _, share := range shares {
+
+		// check that shares all have same id
+		if share.secret_id != shares[0].secret_id {
+			return ErrMismatchedSecretID
+		}
+
+		if share.primitivePoly != shares[0].primitivePoly || share.group != shares[0].group {
+			return ErrMismatchedPolynomial
+		}
+
+		if share.scheme != shares[0].scheme {
+			return ErrMismatchedScheme
+		}
+
+		// check that shares are all same length
+		if len(share.y) != len(shares[0].y) {
+			return ErrInconsistentLength
+		}
+
+		// shares that don't record their threshold are compatible with any threshold
+		if share.threshold != 0 {
+			if threshold != 0 && share.threshold != threshold {
+				return ErrMismatchedThreshold
+			}
+			threshold = share.threshold
+		}
+
 		if _, ok := existingxs[share.x]; ok {
-			return nil, ErrDuplicateShare
+			return ErrDuplicateShare
 		}
-		x[s] = share.x
 		existingxs[share.x] = nil
 	}
 
-	// reconstruct secret
-	for i := range len_secret {
-		y := make([]GfElement, n_shares)
-		for s, share := range shares {
-			y[s] = share.y[i]
-		}
-
-		// compute L(0) by summing terms l_j(0)
-		L := GfElement(0)
-
-		for j := range n_shares {
-			ell := GfElement(1)
-			for k := range n_shares {
-				if k == j {
-					continue
-				}
-				x, err := field.Divide(field.Subtract(GfElement(0), x[k]), field.Subtract(x[j], x[k]))
-				if err != nil {
-					return nil, err
-				}
-				ell = field.Multiply(ell, x)
+	// shares over GF(2^m) are checked before their field is built, since a hostile polynomial or x could exhaust memory or index past the tables
+	if shares[0].group == "" {
+		if err := checkSharePolynomial(shares[0].primitivePoly); err != nil {
+			return err
+		}
+		for _, share := range shares {
+			if share.x < 1 || share.x >= 1<<8 {
+				return ErrXOutOfRange
 			}
-			L = field.Add(L, field.Multiply(y[j], ell))
 		}
+	}
 
-		secret[i] = byte(L)
+	return nil
+}
+
+// recover the secret into memory provided by alloc
+func recoverSecret(shares []Share, o options, alloc func(n int) ([]byte, error)) ([]byte, error) {
+
+	// with trusted dealers configured, every share must be signed by one of them
+	if len(o.trustedDealers) > 0 {
+		for _, share := range shares {
+			if err := share.verifyTrusted(o.trustedDealers); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := ValidateShares(shares); err != nil {
+		return nil, err
+	}
+
+	if shares[0].scheme != SchemeShamir {
+		return recoverDispersed(shares, o, alloc)
+	}
+	if shares[0].group == GroupEd25519 {
+		return recoverScalarSecret(shares, o, alloc)
+	}
+	if shares[0].group != "" {
+		return recoverPrimeSecret(shares, o, alloc)
+	}
+
+	y, yBuf, err := lockedElements(len(shares[0].y))
+	if err != nil {
+		return nil, err
+	}
+	defer yBuf.Close()
+	if err := interpolateGf2m(shares, 0, y); err != nil {
+		return nil, err
+	}
+
+	secret, err := alloc(len(y))
+	if err != nil {
+		return nil, err
+	}
+	for i := range y {
+		secret[i] = byte(y[i])
+	}
+
+	if o.commitment != "" {
+		if err := VerifyRecovered(secret, o.commitment); err != nil {
+			Wipe(secret)
+			return nil, err
+		}
 	}
 
 	return secret, nil
 }
+
+// evaluate the polynomials through the shares over GF(2^m) at x into result, one for each byte of the secret
+func interpolateGf2m(shares []Share, x GfElement, result []GfElement) error {
+	field := NewField(int(shares[0].GetPrimitivePoly()))
+
+	xs := make([]GfElement, len(shares))
+	for s, share := range shares {
+		xs[s] = share.x
+	}
+
+	ys := make([]GfElement, len(shares))
+	defer wipeElements(ys)
+
+	for i := range result {
+		for s, share := range shares {
+			ys[s] = share.y[i]
+		}
+
+		var err error
+		result[i], err = lagrangeInterpolate(field, xs, ys, x)
+		if err != nil {
+			wipeElements(result)
+			return err
+		}
+	}
+
+	return nil
+}
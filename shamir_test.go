@@ -149,3 +149,103 @@ func TestShamirErrors(t *testing.T) {
 		t.Errorf("should have thrown error\n")
 	}
 }
+
+func TestValidateShares(t *testing.T) {
+	s, err := NewShamirSecret(0x11d, 4, 3, []byte("validated"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares := s.GetShares()
+
+	if err := ValidateShares(shares[:2]); err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := NewShamirSecret(0x11d, 4, 2, []byte("different"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherPoly := shares[1]
+	otherPoly.primitivePoly = 0x12b
+
+	otherThreshold := other.GetShares()[1]
+	otherThreshold.secret_id = s.GetId()
+
+	unknownThreshold := NewShare(s.GetId(), 0x11d, 3, shares[2].y)
+
+	for _, test := range []struct {
+		shares []Share
+		want   error
+	}{
+		{nil, ErrNoShares},
+		{[]Share{shares[0], other.GetShares()[1]}, ErrMismatchedSecretID},
+		{[]Share{shares[0], otherPoly}, ErrMismatchedPolynomial},
+		{[]Share{unknownThreshold, shares[0], otherThreshold}, ErrMismatchedThreshold},
+		{[]Share{shares[0], shares[1], shares[0]}, ErrDuplicateShare},
+		{[]Share{shares[0], NewShare(s.GetId(), 0x11d, 2, shares[1].y[1:])}, ErrInconsistentLength},
+	} {
+		if err := ValidateShares(test.shares); err != test.want {
+			t.Errorf("have %v, want %v", err, test.want)
+		}
+	}
+
+	if err := ValidateShares([]Share{unknownThreshold, shares[0]}); err != nil {
+		t.Errorf("share without a threshold should be compatible: %v", err)
+	}
+}
+
+func TestUnsupportedField(t *testing.T) {
+	// bytes of the secret above 15 aren't elements of GF(16), and shares store one byte per y value
+	for _, poly := range []int{0x13, 0x1002b} {
+		if _, err := NewShamirSecret(poly, 3, 2, []byte("hello")); err != ErrUnsupportedField {
+			t.Errorf("0x%x: sharing should fail, got %v", poly, err)
+		}
+		if _, err := NewPackedSecret(poly, 4, 1, 3, []byte("hello")); err != ErrUnsupportedField {
+			t.Errorf("0x%x: packing should fail, got %v", poly, err)
+		}
+	}
+}
+
+func TestHostileShares(t *testing.T) {
+	tests := []struct {
+		input string
+		err   error
+	}{
+		{"shamir-AAAA-101-1-AAAA shamir-AAAA-101-2-AAAA", ErrReduciblePolynomial},
+		{"shamir-AAAA-3-1-AAAA shamir-AAAA-3-2-AAAA", ErrUnsupportedField},
+		{"shamir-AAAA-7fffffff-1-AAAA shamir-AAAA-7fffffff-2-AAAA", ErrUnsupportedField},
+		{"shamir-AAAA-11d-1-AAAA shamir-AAAA-11d-300-AAAA", ErrXOutOfRange},
+		{"shamir-AAAA-11d-0-AAAA shamir-AAAA-11d-1-AAAA", ErrXOutOfRange},
+		{"shamirida-AAAA-101-2-1-AAAA shamirida-AAAA-101-2-2-AAAA", ErrReduciblePolynomial},
+		{"shamirida-AAAA-7fffffff-2-1-AAAA shamirida-AAAA-7fffffff-2-2-AAAA", ErrUnsupportedField},
+		{"shamirida-AAAA-11d-2-1-AAAA shamirida-AAAA-11d-2-256-AAAA", ErrXOutOfRange},
+	}
+
+	for _, test := range tests {
+		shares, err := NewSharesFromString(test.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(shares) != 2 {
+			t.Fatalf("%s: parsed %d shares", test.input, len(shares))
+		}
+		if _, err := RecoverSecret(shares); err != test.err {
+			t.Errorf("%s: have %v, want %v", test.input, err, test.err)
+		}
+		if _, err := Interpolate(shares, 3); err != test.err {
+			t.Errorf("%s: have %v, want %v", test.input, err, test.err)
+		}
+	}
+
+	if _, err := FieldByName("7fffffff"); err != ErrFieldTooLarge {
+		t.Errorf("have %v, want %v", err, ErrFieldTooLarge)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("NewField should refuse a reducible polynomial")
+		}
+	}()
+	NewField(0x101)
+}
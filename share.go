@@ -12,8 +12,13 @@ const SharePrefix string = "shamir"
 type Share struct {
 	secret_id     string
 	primitivePoly int64
+	group         string      // name of the prime-order group or prime field, empty for shares over GF(2^m)
+	scheme        Scheme      // how the secret was split, empty for Shamir's scheme
+	threshold     int         // number of shares needed to reconstruct the secret, 0 if unknown
 	x             GfElement   // x coordinate
 	y             []GfElement // y coordinates
+	dealer        string      // fingerprint of the dealer's signing key, empty if unsigned
+	signature     []byte      // dealer's ed25519 signature, nil if unsigned
 }
 
 func NewShare(secret_id string, primitivePoly int64, x GfElement, y []GfElement) Share {
@@ -21,15 +26,19 @@ func NewShare(secret_id string, primitivePoly int64, x GfElement, y []GfElement)
 }
 
 func NewSharesFromString(input string) ([]Share, error) {
-	r := regexp.MustCompile(`shamir-(\w+)-(\w+)-(\w+)-([\w\+\/]+)`)
+	r := regexp.MustCompile(`shamir-(\w+)-(\w+)-(\w+)-([\w\+\/]+)(?::(\d+):([0-9a-f]+):([\w\+\/]+))?`)
+
+	shares, input, err := parseArmoredShares(input)
+	if err != nil {
+		return nil, err
+	}
 
-	shares := make([]Share, 0)
 	matches := r.FindAllStringSubmatch(input, -1)
 	for _, match := range matches {
 
 		secret_id := string(match[1])
 
-		primitivePoly, err := strconv.ParseInt(string(match[2]), 16, 64)
+		primitivePoly, group, err := parseFieldName(match[2])
 		if err != nil {
 			return nil, err
 		}
@@ -50,19 +59,56 @@ func NewSharesFromString(input string) ([]Share, error) {
 			y[i] = GfElement(ydata[i])
 		}
 
-		shares = append(shares, NewShare(secret_id, primitivePoly, x, y))
+		share := NewShare(secret_id, primitivePoly, x, y)
+		share.group = group
+		if err := share.parseSignature(match[5], match[6], match[7]); err != nil {
+			return nil, err
+		}
+
+		shares = append(shares, share)
 	}
 
-	return shares, nil
+	dispersed, err := parseDispersedShares(input)
+	if err != nil {
+		return nil, err
+	}
 
+	return append(shares, dispersed...), nil
 }
 
 func (share Share) ShareLabel() string {
-	return fmt.Sprintf("%s-%s-%x-%s", SharePrefix, share.secret_id, share.primitivePoly, share.GetXString())
+	// dispersed fragments can't be used without their threshold, so it is part of the label
+	if share.scheme != SchemeShamir {
+		return fmt.Sprintf("%s%s-%s-%s-%d-%s", SharePrefix, share.scheme, share.secret_id, share.fieldName(), share.threshold, share.GetXString())
+	}
+	return fmt.Sprintf("%s-%s-%s-%s", SharePrefix, share.secret_id, share.fieldName(), share.GetXString())
+}
+
+// the primitive polynomial in hex, or the name of the group or prime field
+func (share Share) fieldName() string {
+	if share.group != "" {
+		return share.group
+	}
+	return fmt.Sprintf("%x", share.primitivePoly)
+}
+
+// parse a field name written by fieldName into a primitive polynomial or group
+func parseFieldName(name string) (int64, string, error) {
+	if name == GroupEd25519 {
+		return 0, GroupEd25519, nil
+	}
+
+	// hex never starts with p or s, so prime field names can't be mistaken for polynomials
+	if field, err := PrimeFieldByName(name); err == nil {
+		return 0, field.GetName(), nil
+	}
+
+	primitivePoly, err := strconv.ParseInt(name, 16, 64)
+	return primitivePoly, "", err
 }
 
 func (share Share) String() string {
-	return fmt.Sprintf("%s-%s", share.ShareLabel(), share.GetYString())
+	return fmt.Sprintf("%s-%s%s", share.ShareLabel(), share.GetYString(), share.signatureSuffix())
 }
 
 func (share Share) GetSecretId() string {
@@ -73,14 +119,33 @@ func (share Share) GetPrimitivePoly() int64 {
 	return share.primitivePoly
 }
 
+// name of the prime-order group or prime field, or empty for shares over GF(2^m)
+func (share Share) GetGroup() string {
+	return share.group
+}
+
+// how the secret was split, or SchemeShamir for Shamir's scheme
+func (share Share) GetScheme() Scheme {
+	return share.scheme
+}
+
+// number of shares needed to reconstruct the secret, or 0 if the share doesn't record it
+func (share Share) GetThreshold() int {
+	return share.threshold
+}
+
 func (share Share) GetXString() string {
 	return fmt.Sprintf("%d", share.x)
 }
 
 func (share Share) GetYString() string {
+	return base64.RawStdEncoding.EncodeToString(share.yBytes())
+}
+
+func (share Share) yBytes() []byte {
 	b := make([]byte, len(share.y))
 	for i := range b {
 		b[i] = byte(share.y[i])
 	}
-	return base64.RawStdEncoding.EncodeToString(b)
+	return b
 }
@@ -0,0 +1,126 @@
+package shamir
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// the dealer can sign each share with an ed25519 key, so that holders can't be handed a forged share
+// signed shares look like shamir-<id>-<poly>-<x>-<y>:<threshold>:<dealer fingerprint>:<signature>
+
+var ErrUnsignedShare error = errors.New("share is not signed by the dealer")
+var ErrUntrustedDealer error = errors.New("share is signed by an untrusted dealer")
+var ErrInvalidSignature error = errors.New("share signature is invalid")
+var ErrUnknownThreshold error = errors.New("signed shares must record their threshold")
+
+// WithDealerKey signs each share with the dealer's private key
+func WithDealerKey(key ed25519.PrivateKey) Option {
+	return func(o *options) {
+		o.dealerKey = key
+	}
+}
+
+// WithTrustedDealer rejects shares that aren't signed by one of the trusted dealers
+func WithTrustedDealer(keys ...ed25519.PublicKey) Option {
+	return func(o *options) {
+		o.trustedDealers = append(o.trustedDealers, keys...)
+	}
+}
+
+// Fingerprint identifies a dealer's public key by the first 8 bytes of its SHA-256 hash
+func Fingerprint(key ed25519.PublicKey) string {
+	hash := sha256.Sum256(key)
+	return hex.EncodeToString(hash[:8])
+}
+
+// the message signed by the dealer, covering everything needed to use the share
+func (share Share) signedMessage() []byte {
+	message := fmt.Appendf(nil, "shamir-signature:%s:%s:%d:%d:", share.secret_id, share.fieldName(), share.threshold, share.x)
+	return append(message, share.yBytes()...)
+}
+
+// Sign signs the share with the dealer's private key
+func (share *Share) Sign(key ed25519.PrivateKey) error {
+	if share.threshold < 1 {
+		return ErrUnknownThreshold
+	}
+	if share.scheme != SchemeShamir {
+		return ErrUnsupportedScheme
+	}
+
+	share.dealer = Fingerprint(key.Public().(ed25519.PublicKey))
+	share.signature = ed25519.Sign(key, share.signedMessage())
+	return nil
+}
+
+// Verify checks that the share was signed by the dealer with the given public key
+func (share Share) Verify(key ed25519.PublicKey) error {
+	if !share.IsSigned() {
+		return ErrUnsignedShare
+	}
+	if share.dealer != Fingerprint(key) {
+		return ErrUntrustedDealer
+	}
+	if !ed25519.Verify(key, share.signedMessage(), share.signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// verify the share against whichever trusted dealer signed it
+func (share Share) verifyTrusted(keys []ed25519.PublicKey) error {
+	if !share.IsSigned() {
+		return ErrUnsignedShare
+	}
+
+	i := slices.IndexFunc(keys, func(key ed25519.PublicKey) bool {
+		return Fingerprint(key) == share.dealer
+	})
+	if i < 0 {
+		return ErrUntrustedDealer
+	}
+
+	return share.Verify(keys[i])
+}
+
+func (share Share) IsSigned() bool {
+	return share.signature != nil
+}
+
+// fingerprint of the dealer who signed the share, or empty if it is unsigned
+func (share Share) GetDealer() string {
+	return share.dealer
+}
+
+// the suffix appended to signed share strings
+func (share Share) signatureSuffix() string {
+	if !share.IsSigned() {
+		return ""
+	}
+	return fmt.Sprintf(":%d:%s:%s", share.threshold, share.dealer, base64.RawStdEncoding.EncodeToString(share.signature))
+}
+
+// parse the threshold, dealer fingerprint, and signature captured from a signed share string
+func (share *Share) parseSignature(threshold, dealer, signature string) error {
+	if signature == "" {
+		return nil
+	}
+
+	var err error
+	if _, err = fmt.Sscanf(threshold, "%d", &share.threshold); err != nil {
+		return ErrInvalidSignature
+	}
+
+	share.dealer = dealer
+	share.signature, err = base64.RawStdEncoding.DecodeString(signature)
+	if err != nil || len(share.signature) != ed25519.SignatureSize {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package shamir
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	secret := []byte("signed by the dealer")
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPub, otherPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewShamirSecret(0x11d, 3, 2, secret, WithDealerKey(priv))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// signatures survive the share string, armor, and wrapping
+	wrapped, err := s.GetShares()[2].Wrap([]byte("passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := s.ShareString(0) + "\n" + string(s.GetShares()[1].Armor("", time.Time{})) + wrapped
+	shares, err := UnwrapShares(input, func(label string) ([]byte, error) {
+		return []byte("passphrase"), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shares) != 3 {
+		t.Fatalf("parsed %d shares", len(shares))
+	}
+
+	for _, share := range shares {
+		if share.GetDealer() != Fingerprint(pub) || share.GetThreshold() != 2 {
+			t.Fatalf("share %s lost its signature", share)
+		}
+		if err := share.Verify(pub); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	have, err := RecoverSecret(shares[:2], WithTrustedDealer(otherPub, pub))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(have, secret) {
+		t.Fatalf("have %q, want %q", have, secret)
+	}
+
+	if _, err := RecoverSecret(shares, WithTrustedDealer(otherPub)); err != ErrUntrustedDealer {
+		t.Errorf("have %v, want %v", err, ErrUntrustedDealer)
+	}
+
+	// a share from another dealer with the same secret ID
+	forged := NewShare(shares[0].secret_id, 0x11d, 1, shares[0].y)
+	forged.threshold = 2
+	if err := forged.Sign(otherPriv); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RecoverSecret([]Share{forged, shares[1]}, WithTrustedDealer(pub)); err != ErrUntrustedDealer {
+		t.Errorf("have %v, want %v", err, ErrUntrustedDealer)
+	}
+
+	// a share moved to another x coordinate
+	tampered, err := NewSharesFromString(strings.Replace(s.ShareString(0), "-11d-1-", "-11d-3-", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RecoverSecret([]Share{tampered[0], shares[1]}, WithTrustedDealer(pub)); err != ErrInvalidSignature {
+		t.Errorf("have %v, want %v", err, ErrInvalidSignature)
+	}
+
+	// an unsigned share
+	unsigned := NewShare(shares[0].secret_id, 0x11d, 1, shares[0].y)
+	if _, err := RecoverSecret([]Share{unsigned, shares[1]}, WithTrustedDealer(pub)); err != ErrUnsignedShare {
+		t.Errorf("have %v, want %v", err, ErrUnsignedShare)
+	}
+}
@@ -0,0 +1,60 @@
+package slip39
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	baseIterationCount = 10000 // PBKDF2 iterations, before scaling by the iteration exponent
+	roundCount         = 4     // rounds of the Feistel network
+)
+
+// the master secret is encrypted with a four-round Feistel network using PBKDF2 as the round function
+func encrypt(masterSecret, passphrase []byte, iterationExponent, identifier int, extendable bool) []byte {
+	l := masterSecret[:len(masterSecret)/2]
+	r := masterSecret[len(masterSecret)/2:]
+	salt := cipherSalt(identifier, extendable)
+
+	for i := range roundCount {
+		l, r = r, xor(l, roundFunction(i, passphrase, iterationExponent, salt, r))
+	}
+
+	return append(append([]byte{}, r...), l...)
+}
+
+func decrypt(ems, passphrase []byte, iterationExponent, identifier int, extendable bool) []byte {
+	l := ems[:len(ems)/2]
+	r := ems[len(ems)/2:]
+	salt := cipherSalt(identifier, extendable)
+
+	for i := roundCount - 1; i >= 0; i-- {
+		l, r = r, xor(l, roundFunction(i, passphrase, iterationExponent, salt, r))
+	}
+
+	return append(append([]byte{}, r...), l...)
+}
+
+func roundFunction(i int, passphrase []byte, iterationExponent int, salt, r []byte) []byte {
+	password := append([]byte{byte(i)}, passphrase...)
+	iterations := (baseIterationCount << iterationExponent) / roundCount
+	return pbkdf2.Key(password, append(append([]byte{}, salt...), r...), iterations, len(r), sha256.New)
+}
+
+// extendable shares omit the identifier from the salt, so more groups can be added to a backup later
+func cipherSalt(identifier int, extendable bool) []byte {
+	if extendable {
+		return nil
+	}
+	return binary.BigEndian.AppendUint16([]byte("shamir"), uint16(identifier))
+}
+
+func xor(a, b []byte) []byte {
+	c := make([]byte, len(a))
+	for i := range c {
+		c[i] = a[i] ^ b[i]
+	}
+	return c
+}
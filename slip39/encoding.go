@@ -0,0 +1,88 @@
+package slip39
+
+import (
+	_ "embed"
+	"math/big"
+	"strings"
+)
+
+// the 1024 words of the SLIP-39 word list, each encoding 10 bits
+//
+//go:embed wordlist.txt
+var wordlistText string
+
+var wordlist = strings.Fields(wordlistText)
+
+var wordIndex = func() map[string]int {
+	index := make(map[string]int, len(wordlist))
+	for i, word := range wordlist {
+		index[word] = i
+	}
+	return index
+}()
+
+// split a value into length 10-bit word indices, most significant first
+func intToIndices(value *big.Int, length int) []int {
+	indices := make([]int, length)
+	v := new(big.Int).Set(value)
+	mask := big.NewInt(1<<radixBits - 1)
+	for i := length - 1; i >= 0; i-- {
+		indices[i] = int(new(big.Int).And(v, mask).Int64())
+		v.Rsh(v, radixBits)
+	}
+	return indices
+}
+
+// join 10-bit word indices into a single value, most significant first
+func indicesToInt(indices []int) *big.Int {
+	value := new(big.Int)
+	for _, index := range indices {
+		value.Lsh(value, radixBits)
+		value.Or(value, big.NewInt(int64(index)))
+	}
+	return value
+}
+
+// the RS1024 checksum is a Reed-Solomon code over GF(1024)
+func rs1024Polymod(values []int) int {
+	gen := []int{
+		0xe0e040, 0x1c1c080, 0x3838100, 0x7070200, 0xe0e0009,
+		0x1c0c2412, 0x38086c24, 0x3090fc48, 0x21b1f890, 0x3f3f120,
+	}
+
+	chk := 1
+	for _, v := range values {
+		b := chk >> 20
+		chk = (chk&0xfffff)<<10 ^ v
+		for i := range 10 {
+			if (b>>i)&1 != 0 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+func customizationValues(customization string) []int {
+	values := make([]int, len(customization))
+	for i := range customization {
+		values[i] = int(customization[i])
+	}
+	return values
+}
+
+func createChecksum(customization string, data []int) []int {
+	values := append(customizationValues(customization), data...)
+	values = append(values, make([]int, checksumLengthWords)...)
+
+	polymod := rs1024Polymod(values) ^ 1
+	checksum := make([]int, checksumLengthWords)
+	for i := range checksum {
+		checksum[i] = (polymod >> (radixBits * (checksumLengthWords - 1 - i))) & (1<<radixBits - 1)
+	}
+	return checksum
+}
+
+func verifyChecksum(customization string, data []int) bool {
+	return rs1024Polymod(append(customizationValues(customization), data...)) == 1
+}
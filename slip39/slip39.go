@@ -0,0 +1,427 @@
+// Package slip39 implements SLIP-0039 mnemonic shares, as used by hardware wallets such as the Trezor.
+//
+// See https://github.com/satoshilabs/slips/blob/master/slip-0039.md for the specification.
+package slip39
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/49pctber/shamir"
+)
+
+// SLIP-39 operates over GF(2^8) using the AES polynomial
+const Polynomial int = 0x11b
+
+const (
+	radixBits           = 10 // bits per word
+	idLengthBits        = 15
+	iterationExpBits    = 4
+	idExpLengthWords    = 2
+	paramsLengthWords   = 2
+	checksumLengthWords = 3
+	metadataLengthWords = idExpLengthWords + paramsLengthWords + checksumLengthWords
+	minMnemonicWords    = 20
+	maxShareCount       = 16
+	digestLengthBytes   = 4
+	minStrengthBytes    = 16
+
+	secretIndex = 255 // x coordinate of the shared secret
+	digestIndex = 254 // x coordinate of the digest of the shared secret
+)
+
+var ErrInvalidMnemonic error = errors.New("mnemonic is too short")
+var ErrInvalidWord error = errors.New("mnemonic contains a word that is not in the SLIP-39 word list")
+var ErrInvalidChecksum error = errors.New("mnemonic has an invalid checksum")
+var ErrInvalidPadding error = errors.New("mnemonic has invalid padding")
+var ErrInvalidGroupThreshold error = errors.New("group threshold cannot exceed group count")
+var ErrInvalidCommonParameters error = errors.New("mnemonics have different identifiers, iteration exponents, group thresholds, or group counts")
+var ErrInvalidMemberThreshold error = errors.New("mnemonics in a group have different member thresholds")
+var ErrWrongNumberOfGroups error = errors.New("number of groups does not match the group threshold")
+var ErrWrongNumberOfShares error = errors.New("number of mnemonics in a group does not match the member threshold")
+var ErrDuplicateIndex error = errors.New("mnemonics in a group have the same member index")
+var ErrInconsistentLength error = errors.New("mnemonics have values of different lengths")
+var ErrInvalidDigest error = errors.New("shared secret digest does not match, so the mnemonics are inconsistent")
+var ErrInvalidSecretLength error = errors.New("master secret must be an even number of bytes, and at least 16 bytes long")
+var ErrInvalidShareCount error = errors.New("thresholds must be between 1 and the share count, which cannot exceed 16")
+var ErrInvalidSingleThreshold error = errors.New("a member threshold of 1 requires a member count of 1")
+var ErrInvalidPassphrase error = errors.New("passphrase must contain only printable ASCII characters")
+
+// a single SLIP-39 mnemonic share
+type Share struct {
+	Identifier        int
+	Extendable        bool
+	IterationExponent int
+	GroupIndex        int
+	GroupThreshold    int
+	GroupCount        int
+	MemberIndex       int
+	MemberThreshold   int
+	Value             []byte
+}
+
+// the number of members in a group and how many of them are needed to reconstruct the group's share
+type GroupParameters struct {
+	MemberThreshold int
+	MemberCount     int
+}
+
+// a point used while splitting or recovering a secret
+type rawShare struct {
+	x    shamir.GfElement
+	data []byte
+}
+
+var field = shamir.NewField(Polynomial)
+
+func (share Share) customizationString() string {
+	if share.Extendable {
+		return "shamir_extendable"
+	}
+	return "shamir"
+}
+
+// Words encodes the share as a list of words from the SLIP-39 word list
+func (share Share) Words() []string {
+	idExp := share.Identifier << (iterationExpBits + 1)
+	if share.Extendable {
+		idExp |= 1 << iterationExpBits
+	}
+	idExp |= share.IterationExponent
+
+	params := share.GroupIndex
+	params = params<<4 | (share.GroupThreshold - 1)
+	params = params<<4 | (share.GroupCount - 1)
+	params = params<<4 | share.MemberIndex
+	params = params<<4 | (share.MemberThreshold - 1)
+
+	valueWords := (8*len(share.Value) + radixBits - 1) / radixBits
+
+	data := intToIndices(new(big.Int).SetInt64(int64(idExp)), idExpLengthWords)
+	data = append(data, intToIndices(new(big.Int).SetInt64(int64(params)), paramsLengthWords)...)
+	data = append(data, intToIndices(new(big.Int).SetBytes(share.Value), valueWords)...)
+	data = append(data, createChecksum(share.customizationString(), data)...)
+
+	words := make([]string, len(data))
+	for i, index := range data {
+		words[i] = wordlist[index]
+	}
+	return words
+}
+
+// Mnemonic encodes the share as a space-separated string of words
+func (share Share) Mnemonic() string {
+	return strings.Join(share.Words(), " ")
+}
+
+// ParseShare decodes and validates a mnemonic
+func ParseShare(mnemonic string) (Share, error) {
+	words := strings.Fields(strings.ToLower(mnemonic))
+	if len(words) < minMnemonicWords {
+		return Share{}, ErrInvalidMnemonic
+	}
+
+	paddingBits := (radixBits * (len(words) - metadataLengthWords)) % 16
+	if paddingBits > 8 {
+		return Share{}, ErrInvalidPadding
+	}
+
+	data := make([]int, len(words))
+	for i, word := range words {
+		index, ok := wordIndex[word]
+		if !ok {
+			return Share{}, ErrInvalidWord
+		}
+		data[i] = index
+	}
+
+	var share Share
+	idExp := int(indicesToInt(data[:idExpLengthWords]).Int64())
+	share.Identifier = idExp >> (iterationExpBits + 1)
+	share.Extendable = (idExp>>iterationExpBits)&1 == 1
+	share.IterationExponent = idExp & (1<<iterationExpBits - 1)
+
+	if !verifyChecksum(share.customizationString(), data) {
+		return Share{}, ErrInvalidChecksum
+	}
+
+	params := int(indicesToInt(data[idExpLengthWords : idExpLengthWords+paramsLengthWords]).Int64())
+	share.GroupIndex = (params >> 16) & 0xf
+	share.GroupThreshold = (params>>12)&0xf + 1
+	share.GroupCount = (params>>8)&0xf + 1
+	share.MemberIndex = (params >> 4) & 0xf
+	share.MemberThreshold = params&0xf + 1
+
+	if share.GroupThreshold > share.GroupCount {
+		return Share{}, ErrInvalidGroupThreshold
+	}
+
+	valueData := data[idExpLengthWords+paramsLengthWords : len(data)-checksumLengthWords]
+	valueBytes := (radixBits*len(valueData) - paddingBits) / 8
+	value := indicesToInt(valueData)
+	if value.BitLen() > 8*valueBytes {
+		return Share{}, ErrInvalidPadding
+	}
+	share.Value = value.FillBytes(make([]byte, valueBytes))
+
+	if len(share.Value) < minStrengthBytes || len(share.Value)%2 != 0 {
+		return Share{}, ErrInvalidPadding
+	}
+
+	return share, nil
+}
+
+// Generate splits a master secret into groups of mnemonics.
+// Any groupThreshold of the groups are needed, and within each of those groups, any MemberThreshold of its mnemonics.
+// The iteration exponent sets the cost of decrypting the master secret as 10000 * 2^iterationExponent PBKDF2 iterations.
+func Generate(groupThreshold int, groups []GroupParameters, masterSecret []byte, passphrase []byte, extendable bool, iterationExponent int) ([][]string, error) {
+	if len(masterSecret) < minStrengthBytes || len(masterSecret)%2 != 0 {
+		return nil, ErrInvalidSecretLength
+	}
+	if err := checkPassphrase(passphrase); err != nil {
+		return nil, err
+	}
+	if iterationExponent < 0 || iterationExponent >= 1<<iterationExpBits {
+		return nil, errors.New("iteration exponent must be between 0 and 15")
+	}
+	if groupThreshold > len(groups) {
+		return nil, ErrInvalidGroupThreshold
+	}
+	for _, group := range groups {
+		if group.MemberThreshold == 1 && group.MemberCount > 1 {
+			return nil, ErrInvalidSingleThreshold
+		}
+	}
+
+	id, err := rand.Int(rand.Reader, big.NewInt(1<<idLengthBits))
+	if err != nil {
+		return nil, err
+	}
+	identifier := int(id.Int64())
+
+	ems := encrypt(masterSecret, passphrase, iterationExponent, identifier, extendable)
+
+	groupShares, err := splitSecret(groupThreshold, len(groups), ems)
+	if err != nil {
+		return nil, err
+	}
+
+	mnemonics := make([][]string, len(groups))
+	for g, groupShare := range groupShares {
+		memberShares, err := splitSecret(groups[g].MemberThreshold, groups[g].MemberCount, groupShare.data)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, memberShare := range memberShares {
+			share := Share{
+				Identifier:        identifier,
+				Extendable:        extendable,
+				IterationExponent: iterationExponent,
+				GroupIndex:        g,
+				GroupThreshold:    groupThreshold,
+				GroupCount:        len(groups),
+				MemberIndex:       int(memberShare.x),
+				MemberThreshold:   groups[g].MemberThreshold,
+				Value:             memberShare.data,
+			}
+			mnemonics[g] = append(mnemonics[g], share.Mnemonic())
+		}
+	}
+
+	return mnemonics, nil
+}
+
+// Combine recovers the master secret from exactly the threshold number of mnemonics in exactly the threshold number of groups
+func Combine(mnemonics []string, passphrase []byte) ([]byte, error) {
+	if len(mnemonics) == 0 {
+		return nil, ErrInvalidMnemonic
+	}
+
+	shares := make([]Share, len(mnemonics))
+	for i, mnemonic := range mnemonics {
+		share, err := ParseShare(mnemonic)
+		if err != nil {
+			return nil, err
+		}
+		shares[i] = share
+	}
+
+	// all mnemonics must come from the same split
+	first := shares[0]
+	groups := make(map[int][]Share, 0)
+	for _, share := range shares {
+		if share.Identifier != first.Identifier || share.Extendable != first.Extendable ||
+			share.IterationExponent != first.IterationExponent ||
+			share.GroupThreshold != first.GroupThreshold || share.GroupCount != first.GroupCount {
+			return nil, ErrInvalidCommonParameters
+		}
+		groups[share.GroupIndex] = append(groups[share.GroupIndex], share)
+	}
+
+	if len(groups) != first.GroupThreshold {
+		return nil, ErrWrongNumberOfGroups
+	}
+
+	groupShares := make([]rawShare, 0, len(groups))
+	for groupIndex, members := range groups {
+		memberThreshold := members[0].MemberThreshold
+		for _, member := range members {
+			if member.MemberThreshold != memberThreshold {
+				return nil, ErrInvalidMemberThreshold
+			}
+		}
+		if len(members) != memberThreshold {
+			return nil, ErrWrongNumberOfShares
+		}
+
+		raw := make([]rawShare, len(members))
+		for i, member := range members {
+			raw[i] = rawShare{x: shamir.GfElement(member.MemberIndex), data: member.Value}
+		}
+
+		groupSecret, err := recoverSecret(memberThreshold, raw)
+		if err != nil {
+			return nil, err
+		}
+		groupShares = append(groupShares, rawShare{x: shamir.GfElement(groupIndex), data: groupSecret})
+	}
+
+	ems, err := recoverSecret(first.GroupThreshold, groupShares)
+	if err != nil {
+		return nil, err
+	}
+
+	return decrypt(ems, passphrase, first.IterationExponent, first.Identifier, first.Extendable), nil
+}
+
+// split a secret so that any threshold of the shares recover it
+// the polynomial passes through the secret at x=255 and a digest of the secret at x=254
+func splitSecret(threshold int, count int, secret []byte) ([]rawShare, error) {
+	if threshold < 1 || threshold > count || count > maxShareCount {
+		return nil, ErrInvalidShareCount
+	}
+
+	shares := make([]rawShare, 0, count)
+
+	if threshold == 1 {
+		for i := range count {
+			shares = append(shares, rawShare{x: shamir.GfElement(i), data: secret})
+		}
+		return shares, nil
+	}
+
+	// the first threshold-2 shares are random
+	for i := range threshold - 2 {
+		data := make([]byte, len(secret))
+		if _, err := rand.Read(data); err != nil {
+			return nil, err
+		}
+		shares = append(shares, rawShare{x: shamir.GfElement(i), data: data})
+	}
+
+	randomPart := make([]byte, len(secret)-digestLengthBytes)
+	if _, err := rand.Read(randomPart); err != nil {
+		return nil, err
+	}
+
+	base := append([]rawShare{}, shares...)
+	base = append(base, rawShare{x: digestIndex, data: append(createDigest(randomPart, secret), randomPart...)})
+	base = append(base, rawShare{x: secretIndex, data: secret})
+
+	for i := threshold - 2; i < count; i++ {
+		data, err := interpolate(base, shamir.GfElement(i))
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, rawShare{x: shamir.GfElement(i), data: data})
+	}
+
+	return shares, nil
+}
+
+// recover a secret split by splitSecret, checking its digest
+func recoverSecret(threshold int, shares []rawShare) ([]byte, error) {
+	if threshold == 1 {
+		return shares[0].data, nil
+	}
+
+	secret, err := interpolate(shares, secretIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	digestShare, err := interpolate(shares, digestIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := digestShare[:digestLengthBytes]
+	if !hmac.Equal(digest, createDigest(digestShare[digestLengthBytes:], secret)) {
+		return nil, ErrInvalidDigest
+	}
+
+	return secret, nil
+}
+
+// evaluate the polynomial through the shares at x using Lagrange interpolation
+func interpolate(shares []rawShare, x shamir.GfElement) ([]byte, error) {
+	existingxs := make(map[shamir.GfElement]any, 0)
+	for _, share := range shares {
+		if _, ok := existingxs[share.x]; ok {
+			return nil, ErrDuplicateIndex
+		}
+		existingxs[share.x] = nil
+
+		if len(share.data) != len(shares[0].data) {
+			return nil, ErrInconsistentLength
+		}
+	}
+
+	for _, share := range shares {
+		if share.x == x {
+			return bytes.Clone(share.data), nil
+		}
+	}
+
+	result := make([]byte, len(shares[0].data))
+	for j, share := range shares {
+		ell := shamir.GfElement(1)
+		for k, other := range shares {
+			if k == j {
+				continue
+			}
+			term, err := field.Divide(field.Subtract(x, other.x), field.Subtract(share.x, other.x))
+			if err != nil {
+				return nil, err
+			}
+			ell = field.Multiply(ell, term)
+		}
+
+		for i := range result {
+			result[i] = byte(field.Add(shamir.GfElement(result[i]), field.Multiply(shamir.GfElement(share.data[i]), ell)))
+		}
+	}
+
+	return result, nil
+}
+
+func createDigest(randomPart, secret []byte) []byte {
+	mac := hmac.New(sha256.New, randomPart)
+	mac.Write(secret)
+	return mac.Sum(nil)[:digestLengthBytes]
+}
+
+func checkPassphrase(passphrase []byte) error {
+	for _, c := range passphrase {
+		if c < 32 || c > 126 {
+			return ErrInvalidPassphrase
+		}
+	}
+	return nil
+}
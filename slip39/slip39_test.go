@@ -0,0 +1,108 @@
+package slip39
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestVectors(t *testing.T) {
+	// official test vectors from https://github.com/trezor/python-shamir-mnemonic
+	raw, err := os.ReadFile("testdata/vectors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var vectors [][]any
+	if err := json.Unmarshal(raw, &vectors); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, vector := range vectors {
+		description := vector[0].(string)
+		mnemonics := make([]string, 0)
+		for _, m := range vector[1].([]any) {
+			mnemonics = append(mnemonics, m.(string))
+		}
+		want := vector[2].(string)
+
+		have, err := Combine(mnemonics, []byte("TREZOR"))
+		if want == "" {
+			if err == nil {
+				t.Errorf("%s: expected an error", description)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", description, err)
+			continue
+		}
+		if hex.EncodeToString(have) != want {
+			t.Errorf("%s: have %x, want %s", description, have, want)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	passphrase := []byte("correct horse")
+	groups := []GroupParameters{{1, 1}, {2, 3}, {3, 5}}
+
+	for _, extendable := range []bool{false, true} {
+		mnemonics, err := Generate(2, groups, secret, passphrase, extendable, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i, group := range mnemonics {
+			if len(group) != groups[i].MemberCount {
+				t.Fatalf("group %d has %d mnemonics, want %d", i, len(group), groups[i].MemberCount)
+			}
+		}
+
+		selected := []string{mnemonics[2][4], mnemonics[1][2], mnemonics[2][0], mnemonics[1][0], mnemonics[2][1]}
+		have, err := Combine(selected, passphrase)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(have, secret) {
+			t.Errorf("have %x, want %x", have, secret)
+		}
+
+		// a mnemonic must survive a round trip through ParseShare
+		share, err := ParseShare(mnemonics[0][0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if share.Mnemonic() != mnemonics[0][0] || share.Extendable != extendable {
+			t.Errorf("mnemonic did not round trip")
+		}
+
+		// too few members of a group
+		if _, err := Combine(selected[1:], passphrase); err != ErrWrongNumberOfShares {
+			t.Errorf("have %v, want %v", err, ErrWrongNumberOfShares)
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	secret := make([]byte, 16)
+
+	if _, err := Generate(1, []GroupParameters{{1, 1}}, secret[:15], nil, false, 0); err != ErrInvalidSecretLength {
+		t.Errorf("have %v, want %v", err, ErrInvalidSecretLength)
+	}
+	if _, err := Generate(2, []GroupParameters{{1, 1}}, secret, nil, false, 0); err != ErrInvalidGroupThreshold {
+		t.Errorf("have %v, want %v", err, ErrInvalidGroupThreshold)
+	}
+	if _, err := Generate(1, []GroupParameters{{1, 2}}, secret, nil, false, 0); err != ErrInvalidSingleThreshold {
+		t.Errorf("have %v, want %v", err, ErrInvalidSingleThreshold)
+	}
+	if _, err := Generate(1, []GroupParameters{{3, 17}}, secret, nil, false, 0); err != ErrInvalidShareCount {
+		t.Errorf("have %v, want %v", err, ErrInvalidShareCount)
+	}
+	if _, err := Generate(1, []GroupParameters{{1, 1}}, secret, []byte("café"), false, 0); err != ErrInvalidPassphrase {
+		t.Errorf("have %v, want %v", err, ErrInvalidPassphrase)
+	}
+}
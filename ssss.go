@@ -0,0 +1,286 @@
+package shamir
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// compatibility with B. Poettering's ssss-split and ssss-combine
+// ssss shares the whole secret as a single element of GF(2^(8*len)), and prints shares as [token-]index-hex
+
+const ssssMaxDegree int = 1024
+
+var ErrInvalidSsssShare error = errors.New("ssss shares must look like [token-]index-hex")
+var ErrInvalidSsssDegree error = errors.New("ssss security level must be a multiple of 8 between 8 and 1024 bits")
+var ErrNotEnoughSsssShares error = errors.New("fewer ssss shares than the threshold")
+var ErrMismatchedSsssShares error = errors.New("ssss shares have different tokens or security levels")
+
+// the three middle exponents of the irreducible pentanomial x^deg + x^a + x^b + x^c + 1 for deg = 8, 16, ..., 1024
+var ssssIrreducibleCoefficients = []uint{
+	4, 3, 1, 5, 3, 1, 4, 3, 1, 7, 3, 2, 5, 4, 3, 5, 3, 2, 7, 4, 2, 4, 3, 1, 10, 9, 3, 9, 4, 2, 7, 6, 2, 10, 9,
+	6, 4, 3, 1, 5, 4, 3, 4, 3, 1, 7, 2, 1, 5, 3, 2, 7, 4, 2, 6, 3, 2, 5, 3, 2, 15, 3, 2, 11, 3, 2, 9, 8, 7, 7,
+	2, 1, 5, 3, 2, 9, 3, 1, 7, 3, 1, 9, 8, 3, 9, 4, 2, 8, 5, 3, 15, 14, 10, 10, 5, 2, 9, 6, 2, 9, 3, 2, 9, 5,
+	2, 11, 10, 1, 7, 3, 2, 11, 2, 1, 9, 7, 4, 4, 3, 1, 8, 3, 1, 7, 4, 1, 7, 2, 1, 13, 11, 6, 5, 3, 2, 7, 3, 2,
+	8, 7, 5, 12, 3, 2, 13, 10, 6, 5, 3, 2, 5, 3, 2, 9, 5, 2, 9, 7, 2, 13, 4, 3, 4, 3, 1, 11, 6, 4, 18, 9, 6,
+	19, 18, 13, 11, 3, 2, 15, 9, 6, 4, 3, 1, 16, 5, 2, 15, 14, 6, 8, 5, 2, 15, 11, 2, 11, 6, 2, 7, 5, 3, 8,
+	3, 1, 19, 16, 9, 11, 9, 6, 15, 7, 6, 13, 4, 3, 14, 13, 3, 13, 6, 3, 9, 5, 2, 19, 13, 6, 19, 10, 3, 11,
+	6, 5, 9, 2, 1, 14, 3, 2, 13, 3, 1, 7, 5, 4, 11, 9, 8, 11, 6, 5, 23, 16, 9, 19, 14, 6, 23, 10, 2, 8, 3,
+	2, 5, 4, 3, 9, 6, 4, 4, 3, 2, 13, 8, 6, 13, 11, 1, 13, 10, 3, 11, 6, 5, 19, 17, 4, 15, 14, 7, 13, 9, 6,
+	9, 7, 3, 9, 7, 1, 14, 3, 2, 11, 8, 2, 11, 6, 4, 13, 5, 2, 11, 5, 1, 11, 4, 1, 19, 10, 3, 21, 10, 6, 13,
+	3, 1, 15, 7, 5, 19, 18, 10, 7, 5, 3, 12, 7, 2, 7, 5, 1, 14, 9, 6, 10, 3, 2, 15, 13, 12, 12, 11, 9, 16,
+	9, 7, 12, 9, 3, 9, 5, 2, 17, 10, 6, 24, 9, 3, 17, 15, 13, 5, 4, 3, 19, 17, 8, 15, 6, 3, 19, 6, 1,
+}
+
+type SsssShare struct {
+	token  string   // optional label shared by all shares of a secret
+	x      int      // share index
+	y      *big.Int // value of the sharing polynomial at x
+	degree int      // security level in bits
+}
+
+func (share SsssShare) String() string {
+	s := fmt.Sprintf("%d-%0*x", share.x, share.degree/4, share.y)
+	if share.token != "" {
+		s = share.token + "-" + s
+	}
+	return s
+}
+
+func (share SsssShare) GetToken() string {
+	return share.token
+}
+
+// construct the field ssss uses for a given security level
+func NewSsssField(degree int) (Gf2mBig, error) {
+	if degree < 8 || degree > ssssMaxDegree || degree%8 != 0 {
+		return Gf2mBig{}, ErrInvalidSsssDegree
+	}
+
+	poly := new(big.Int)
+	poly.SetBit(poly, degree, 1)
+	for _, exponent := range ssssIrreducibleCoefficients[3*(degree/8-1) : 3*(degree/8)] {
+		poly.SetBit(poly, int(exponent), 1)
+	}
+	poly.SetBit(poly, 0, 1)
+
+	return NewBigField(poly), nil
+}
+
+// NewSsssSharesFromString parses whitespace-separated shares printed by ssss-split
+func NewSsssSharesFromString(input string) ([]SsssShare, error) {
+	shares := make([]SsssShare, 0)
+
+	for _, field := range strings.Fields(input) {
+		parts := strings.Split(field, "-")
+
+		var share SsssShare
+		switch len(parts) {
+		case 2:
+		case 3:
+			share.token = parts[0]
+			parts = parts[1:]
+		default:
+			return nil, ErrInvalidSsssShare
+		}
+
+		x, err := strconv.Atoi(parts[0])
+		if err != nil || x < 1 {
+			return nil, ErrInvalidSsssShare
+		}
+
+		y, ok := new(big.Int).SetString(parts[1], 16)
+		if !ok {
+			return nil, ErrInvalidSsssShare
+		}
+
+		share.x = x
+		share.y = y
+		share.degree = 4 * len(parts[1])
+
+		shares = append(shares, share)
+	}
+
+	return shares, nil
+}
+
+// SplitSsss divides a secret as ssss-split does, so the shares can be combined by ssss-combine.
+// The security level is the length of the secret in bits, and diffusion is applied when it is at least 64 bits.
+func SplitSsss(token string, nshares int, threshold int, secret []byte) ([]SsssShare, error) {
+	if threshold > nshares {
+		return nil, ErrThresholdTooLarge
+	}
+
+	degree := 8 * len(secret)
+	field, err := NewSsssField(degree)
+	if err != nil {
+		return nil, err
+	}
+
+	coefficients := make([]*big.Int, threshold)
+	coefficients[0] = new(big.Int).SetBytes(secret)
+	if degree >= 64 {
+		coefficients[0] = ssssDiffuse(coefficients[0], degree, true)
+	}
+
+	for i := 1; i < threshold; i++ {
+		coefficients[i], err = field.Random()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	shares := make([]SsssShare, nshares)
+	for i := range shares {
+		x := big.NewInt(int64(i + 1))
+
+		// ssss evaluates the monic polynomial x^k + c_{k-1} x^{k-1} + ... + c_0
+		y := new(big.Int).Set(x)
+		for j := threshold - 1; j > 0; j-- {
+			y = field.Multiply(field.Add(y, coefficients[j]), x)
+		}
+		y = field.Add(y, coefficients[0])
+
+		shares[i] = SsssShare{token: token, x: i + 1, y: y, degree: degree}
+	}
+
+	return shares, nil
+}
+
+// CombineSsss recovers a secret from the first threshold ssss shares.
+// ssss uses a polynomial whose degree is the threshold, so unlike RecoverSecret, the threshold must be known.
+// Like ssss-combine, leading zero bytes of the secret are not preserved.
+func CombineSsss(shares []SsssShare, threshold int, diffusion bool) ([]byte, error) {
+	if threshold < 1 || len(shares) < threshold {
+		return nil, ErrNotEnoughSsssShares
+	}
+	shares = shares[:threshold]
+
+	degree := shares[0].degree
+	for _, share := range shares {
+		if share.degree != degree || share.token != shares[0].token {
+			return nil, ErrMismatchedSsssShares
+		}
+	}
+
+	field, err := NewSsssField(degree)
+	if err != nil {
+		return nil, err
+	}
+
+	existingxs := make(map[int]any, 0)
+	for _, share := range shares {
+		if _, ok := existingxs[share.x]; ok {
+			return nil, ErrDuplicateShare
+		}
+		existingxs[share.x] = nil
+	}
+
+	// remove the monic term, then interpolate the remaining polynomial at x=0
+	k := len(shares)
+	x := make([]*big.Int, k)
+	y := make([]*big.Int, k)
+	for i, share := range shares {
+		x[i] = big.NewInt(int64(share.x))
+
+		xk := big.NewInt(1)
+		for range k {
+			xk = field.Multiply(xk, x[i])
+		}
+		y[i] = field.Add(share.y, xk)
+	}
+
+	secret, err := lagrangeInterpolate(field, x, y, field.Element(0))
+	if err != nil {
+		return nil, err
+	}
+
+	if diffusion && degree >= 64 {
+		secret = ssssDiffuse(secret, degree, false)
+	}
+
+	return secret.Bytes(), nil
+}
+
+// the diffusion layer ssss applies to the secret before sharing it
+// each pass enciphers 8 bytes with a keyless XTEA, sliding 2 bytes at a time around the secret
+func ssssDiffuse(x *big.Int, degree int, encode bool) *big.Int {
+	n := degree / 8
+
+	// ssss operates on 16-bit big-endian words, least significant word first
+	words := (degree + 8) / 16
+	raw := x.FillBytes(make([]byte, 2*words))
+	v := make([]byte, 2*words)
+	for w := range words {
+		v[2*w] = raw[len(raw)-2-2*w]
+		v[2*w+1] = raw[len(raw)-1-2*w]
+	}
+
+	// when there are an odd number of bytes, close the gap left by the most significant word
+	if degree%16 == 8 {
+		v[n-1] = v[n]
+	}
+
+	if encode {
+		for i := 0; i < 40*n; i += 2 {
+			ssssProcessSlice(v[:n], i, ssssEncipherBlock)
+		}
+	} else {
+		for i := 40*n - 2; i >= 0; i -= 2 {
+			ssssProcessSlice(v[:n], i, ssssDecipherBlock)
+		}
+	}
+
+	if degree%16 == 8 {
+		v[n] = v[n-1]
+		v[n-1] = 0
+	}
+
+	for w := range words {
+		raw[len(raw)-2-2*w] = v[2*w]
+		raw[len(raw)-1-2*w] = v[2*w+1]
+	}
+
+	return new(big.Int).SetBytes(raw)
+}
+
+func ssssProcessSlice(data []byte, idx int, process func(v *[2]uint32)) {
+	n := len(data)
+
+	var v [2]uint32
+	for i := range v {
+		v[i] = uint32(data[(idx+4*i)%n])<<24 | uint32(data[(idx+4*i+1)%n])<<16 |
+			uint32(data[(idx+4*i+2)%n])<<8 | uint32(data[(idx+4*i+3)%n])
+	}
+
+	process(&v)
+
+	for i := range v {
+		data[(idx+4*i)%n] = byte(v[i] >> 24)
+		data[(idx+4*i+1)%n] = byte(v[i] >> 16)
+		data[(idx+4*i+2)%n] = byte(v[i] >> 8)
+		data[(idx+4*i+3)%n] = byte(v[i])
+	}
+}
+
+const ssssDelta uint32 = 0x9e3779b9
+
+func ssssEncipherBlock(v *[2]uint32) {
+	sum := uint32(0)
+	for range 32 {
+		v[0] += (((v[1] << 4) ^ (v[1] >> 5)) + v[1]) ^ sum
+		sum += ssssDelta
+		v[1] += (((v[0] << 4) ^ (v[0] >> 5)) + v[0]) ^ sum
+	}
+}
+
+func ssssDecipherBlock(v *[2]uint32) {
+	sum := uint32(0xc6ef3720) // 32 * ssssDelta, truncated to 32 bits
+	for range 32 {
+		v[1] -= (((v[0] << 4) ^ (v[0] >> 5)) + v[0]) ^ sum
+		sum -= ssssDelta
+		v[0] -= (((v[1] << 4) ^ (v[1] >> 5)) + v[1]) ^ sum
+	}
+}
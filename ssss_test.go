@@ -0,0 +1,116 @@
+package shamir
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func TestSsssExample(t *testing.T) {
+	// example from the ssss documentation, split with `ssss-split -t 3 -n 5`
+	input := `1-1c41ef496eccfbeba439714085df8437236298da8dd824
+2-fbc74a03a50e14ab406c225afb5f45c40ae11976d2b665
+3-fa1c3a9c6df8af0779c36de6c33f6e36e989d0e0b91309
+4-468de7d6eb36674c9cf008c8e8fc8c566537ad6301eb9e
+5-4756974923c0dce0a55f4774d09ca7a4865f64f56a4ee0`
+
+	shares, err := NewSsssSharesFromString(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, selected := range [][]SsssShare{shares[2:5], {shares[2], shares[4], shares[1]}, shares[0:3]} {
+		have, err := CombineSsss(selected, 3, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []byte("my secret root password"); !bytes.Equal(have, want) {
+			t.Errorf("have %q, want %q", have, want)
+		}
+	}
+}
+
+func TestSsss(t *testing.T) {
+	for _, secret := range []string{"short", "eight by", "odd length secret", "This secret is long enough to need a larger field."} {
+		shares, err := SplitSsss("token", 5, 3, []byte(secret))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		input := shares[4].String() + "\n" + shares[1].String() + "\n" + shares[3].String()
+		parsed, err := NewSsssSharesFromString(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if parsed[0].GetToken() != "token" {
+			t.Errorf("have token %s, want token", parsed[0].GetToken())
+		}
+
+		have, err := CombineSsss(parsed, 3, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(have) != secret {
+			t.Errorf("have %q, want %q", have, secret)
+		}
+
+		// too few shares should not recover the secret
+		if _, err := CombineSsss(parsed[:2], 3, true); err == nil {
+			t.Error("should have thrown an error with too few shares")
+		}
+	}
+}
+
+func TestSsssErrors(t *testing.T) {
+	if _, err := NewSsssSharesFromString("1-abc-def-012"); err == nil {
+		t.Error("should have rejected malformed share")
+	}
+
+	shares, err := NewSsssSharesFromString("1-0102 2-0304")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CombineSsss([]SsssShare{shares[0], shares[0]}, 2, true); err == nil {
+		t.Error("should have thrown an error with duplicate shares")
+	}
+
+	shares, err = NewSsssSharesFromString("1-0102 2-030405")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CombineSsss(shares, 2, true); err == nil {
+		t.Error("should have thrown an error with mismatched security levels")
+	}
+}
+
+func TestBigField(t *testing.T) {
+	small := NewField(0x11d)
+	field := NewBigField(big.NewInt(0x11d))
+
+	for a := 0; a < 256; a++ {
+		for b := 0; b < 256; b += 7 {
+			have := field.Multiply(big.NewInt(int64(a)), big.NewInt(int64(b)))
+			want := small.Multiply(GfElement(a), GfElement(b))
+			if have.Int64() != int64(want) {
+				t.Fatalf("%d*%d: have %d, want %d", a, b, have, want)
+			}
+		}
+
+		if a == 0 {
+			continue
+		}
+
+		inverse, err := field.Inverse(big.NewInt(int64(a)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have := field.Multiply(inverse, big.NewInt(int64(a))); have.Int64() != 1 {
+			t.Errorf("%d * inverse = %d, not 1", a, have)
+		}
+	}
+
+	if _, err := field.Divide(big.NewInt(1), big.NewInt(0)); err == nil {
+		t.Error("should have thrown a division by zero error")
+	}
+}
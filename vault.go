@@ -0,0 +1,88 @@
+package shamir
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// HashiCorp Vault splits its root key over GF(2^8) using the AES polynomial
+const VaultPolynomial int = 0x11b
+
+// Vault shares carry no secret ID, so imported shares are assigned this one unless another is given
+const VaultSecretId string = "VAULT"
+
+var ErrInvalidVaultShare error = errors.New("vault shares must be at least two bytes long")
+var ErrNotVaultCompatible error = errors.New("share cannot be represented in vault's format")
+
+// NewShareFromVault decodes a share in the byte layout used by Vault.
+// Vault stores the y coordinates followed by a single byte holding the x coordinate.
+func NewShareFromVault(secret_id string, part []byte) (Share, error) {
+	if len(part) < 2 {
+		return Share{}, ErrInvalidVaultShare
+	}
+
+	y := make([]GfElement, len(part)-1)
+	for i := range y {
+		y[i] = GfElement(part[i])
+	}
+
+	x := GfElement(part[len(part)-1])
+	if x == 0 {
+		return Share{}, ErrInvalidVaultShare
+	}
+
+	return NewShare(secret_id, int64(VaultPolynomial), x, y), nil
+}
+
+// NewSharesFromVaultString parses whitespace-separated Vault shares encoded in base64, as printed by `vault operator init`
+func NewSharesFromVaultString(secret_id string, input string) ([]Share, error) {
+	return newSharesFromVault(secret_id, input, base64.StdEncoding.DecodeString)
+}
+
+// NewSharesFromVaultHexString parses whitespace-separated Vault shares encoded in hex, as in unseal_keys_hex.
+// Some base64 shares are also valid hex, so the encoding is never guessed.
+func NewSharesFromVaultHexString(secret_id string, input string) ([]Share, error) {
+	return newSharesFromVault(secret_id, input, hex.DecodeString)
+}
+
+func newSharesFromVault(secret_id string, input string, decode func(string) ([]byte, error)) ([]Share, error) {
+	shares := make([]Share, 0)
+
+	for _, field := range strings.Fields(input) {
+		part, err := decode(field)
+		if err != nil {
+			return nil, err
+		}
+
+		share, err := NewShareFromVault(secret_id, part)
+		if err != nil {
+			return nil, err
+		}
+
+		shares = append(shares, share)
+	}
+
+	return shares, nil
+}
+
+// Vault encodes the share in the byte layout used by Vault.
+// Only shares over the AES polynomial with an x coordinate that fits in a byte can be combined by Vault.
+func (share Share) Vault() ([]byte, error) {
+	if share.scheme != SchemeShamir || share.primitivePoly != int64(VaultPolynomial) || share.x <= 0 || share.x > 0xff {
+		return nil, ErrNotVaultCompatible
+	}
+
+	return append(share.yBytes(), byte(share.x)), nil
+}
+
+// VaultString encodes the share in Vault's layout using base64, as printed by `vault operator init`
+func (share Share) VaultString() (string, error) {
+	part, err := share.Vault()
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(part), nil
+}
@@ -0,0 +1,136 @@
+package shamir
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestTablesAES(t *testing.T) {
+	field := NewField(VaultPolynomial)
+
+	for element := GfElement(1); element < 256; element++ {
+		power := field.logTable[element]
+		polyBack := field.antilogTable[power]
+		if element != polyBack {
+			t.Errorf("exp^log(%d) != %d", element, polyBack)
+		}
+	}
+
+	// multiplication from the AES specification
+	if have, want := field.Multiply(0x57, 0x83), GfElement(0xc1); have != want {
+		t.Errorf("have %x, want %x", have, want)
+	}
+}
+
+func TestVault(t *testing.T) {
+	// shares produced by Vault's shamir.Split
+	parts := []string{
+		"5pEMXgJb0bd42jeMbBl1qFpKBBTI",
+		"Q/5eQiVNPjTcrSiTgWhTlnr+yftJ",
+		"AH052aYisQNVczFctd/UTjlUDTsX",
+		"9v/YiheznnYSeI87sh8juwnknpCe",
+		"XAlBSNqJovpnewXdohi++YF4ilgG",
+	}
+	want := []byte("Vault interop secret")
+
+	selected := []string{parts[4], parts[0], parts[2]}
+
+	shares, err := NewSharesFromVaultString(VaultSecretId, strings.Join(selected, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	have, err := RecoverSecret(shares)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(have, want) {
+		t.Fatalf("have %q, want %q", have, want)
+	}
+
+	for i, share := range shares {
+		encoded, err := share.VaultString()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if encoded != selected[i] {
+			t.Errorf("have %s, want %s", encoded, selected[i])
+		}
+	}
+}
+
+func TestVaultExport(t *testing.T) {
+	secret := []byte("exported to vault")
+
+	shamir, err := NewShamirSecret(VaultPolynomial, 4, 3, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := ""
+	for _, share := range shamir.GetShares()[1:] {
+		part, err := share.Vault()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(part) != len(secret)+1 {
+			t.Fatalf("have %d bytes, want %d", len(part), len(secret)+1)
+		}
+		input += " " + strings.ToUpper(hex.EncodeToString(part))
+	}
+
+	shares, err := NewSharesFromVaultHexString(VaultSecretId, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	have, err := RecoverSecret(shares)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(have, secret) {
+		t.Fatalf("have %q, want %q", have, secret)
+	}
+
+	share := NewShare("ABC", 0x11d, 1, []GfElement{1, 2, 3})
+	if _, err := share.Vault(); err == nil {
+		t.Error("should not be able to export a share over a different field")
+	}
+}
+
+func TestVaultEncoding(t *testing.T) {
+	// this base64 share is also valid hex, so it must not be decoded as hex
+	const ambiguous = "deadbeef"
+	part, err := base64.StdEncoding.DecodeString(ambiguous)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shares, err := NewSharesFromVaultString(VaultSecretId, ambiguous)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shares) != 1 || len(shares[0].y) != len(part)-1 || shares[0].x != GfElement(part[len(part)-1]) {
+		t.Errorf("base64 share was decoded wrongly: %v", shares)
+	}
+	if encoded, _ := shares[0].VaultString(); encoded != ambiguous {
+		t.Errorf("have %s, want %s", encoded, ambiguous)
+	}
+
+	shares, err = NewSharesFromVaultHexString(VaultSecretId, ambiguous)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shares) != 1 || shares[0].x != 0xef {
+		t.Errorf("hex share was decoded wrongly: %v", shares)
+	}
+
+	if _, err := NewSharesFromVaultHexString(VaultSecretId, "5pEMXgJb0bd42jeMbBl1qFpKBBTI"); err == nil {
+		t.Error("base64 share should not parse as hex")
+	}
+}
@@ -0,0 +1,158 @@
+package shamir
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// a share can be wrapped with a passphrase, so that a stolen card is useless without it
+// wrapped shares look like shamirpw-<id>-<poly>-<x>-<base64 salt|nonce|ciphertext>, followed by the signature of a signed share
+
+const WrappedSharePrefix string = "shamirpw"
+
+// Argon2id parameters, the second recommended option of RFC 9106
+const (
+	wrapTime    uint32 = 3
+	wrapMemory  uint32 = 64 * 1024 // KiB
+	wrapThreads uint8  = 4
+	wrapSaltLen int    = 16
+)
+
+var ErrWrongPassphrase error = errors.New("wrong passphrase, or the wrapped share has been modified")
+
+var wrappedRegexp = regexp.MustCompile(`shamirpw-(\w+)-(\w+)-(\w+)-([\w\+\/]+)(?::(\d+):([0-9a-f]+):([\w\+\/]+))?`)
+
+// supplies the passphrase for the wrapped share with the given label, which is wiped once it has been used
+type PassphraseFunc func(label string) ([]byte, error)
+
+func wrapKey(passphrase, salt []byte) []byte {
+	return argon2.IDKey(passphrase, salt, wrapTime, wrapMemory, wrapThreads, chacha20poly1305.KeySize)
+}
+
+// the label of a wrapped share, which is authenticated along with its y values
+func (share Share) wrappedLabel() string {
+	return fmt.Sprintf("%s-%s-%s-%s", WrappedSharePrefix, share.secret_id, share.fieldName(), share.GetXString())
+}
+
+// Wrap encrypts the share's y values with a key derived from the passphrase, returning the wrapped share string
+func (share Share) Wrap(passphrase []byte) (string, error) {
+	if share.scheme != SchemeShamir {
+		return "", ErrUnsupportedScheme
+	}
+
+	salt := make([]byte, wrapSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := wrapKey(passphrase, salt)
+	defer Wipe(key)
+
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	ydata := share.yBytes()
+	defer Wipe(ydata)
+
+	label := share.wrappedLabel()
+	blob := append(salt, nonce...)
+	blob = aead.Seal(blob, nonce, ydata, []byte(label))
+
+	return fmt.Sprintf("%s-%s%s", label, base64.RawStdEncoding.EncodeToString(blob), share.signatureSuffix()), nil
+}
+
+// IsWrapped reports whether the input contains passphrase-wrapped shares
+func IsWrapped(input string) bool {
+	return wrappedRegexp.MatchString(input)
+}
+
+// UnwrapShares unwraps each wrapped share in the input using the passphrase supplied for it, and parses any unwrapped shares as well
+func UnwrapShares(input string, passphrase PassphraseFunc) ([]Share, error) {
+	shares := make([]Share, 0)
+
+	for _, match := range wrappedRegexp.FindAllStringSubmatch(input, -1) {
+		primitivePoly, group, err := parseFieldName(match[2])
+		if err != nil {
+			return nil, err
+		}
+
+		x, err := strconv.ParseInt(match[3], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		blob, err := base64.RawStdEncoding.DecodeString(match[4])
+		if err != nil {
+			return nil, err
+		}
+
+		share := NewShare(match[1], primitivePoly, GfElement(x), nil)
+		share.group = group
+		if err := share.parseSignature(match[5], match[6], match[7]); err != nil {
+			return nil, err
+		}
+		share.y, err = unwrapY(share.wrappedLabel(), blob, passphrase)
+		if err != nil {
+			return nil, err
+		}
+
+		shares = append(shares, share)
+	}
+
+	unwrapped, err := NewSharesFromString(wrappedRegexp.ReplaceAllString(input, ""))
+	if err != nil {
+		return nil, err
+	}
+
+	return append(shares, unwrapped...), nil
+}
+
+// decrypt the y values of a wrapped share, wiping the passphrase and the key derived from it
+func unwrapY(label string, blob []byte, passphrase PassphraseFunc) ([]GfElement, error) {
+	if len(blob) < wrapSaltLen+chacha20poly1305.NonceSize+chacha20poly1305.Overhead {
+		return nil, ErrWrongPassphrase
+	}
+	salt := blob[:wrapSaltLen]
+	nonce := blob[wrapSaltLen : wrapSaltLen+chacha20poly1305.NonceSize]
+	ciphertext := blob[wrapSaltLen+chacha20poly1305.NonceSize:]
+
+	pass, err := passphrase(label)
+	if err != nil {
+		return nil, err
+	}
+	defer Wipe(pass)
+
+	key := wrapKey(pass, salt)
+	defer Wipe(key)
+
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+
+	ydata, err := aead.Open(nil, nonce, ciphertext, []byte(label))
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	defer Wipe(ydata)
+
+	y := make([]GfElement, len(ydata))
+	for i := range ydata {
+		y[i] = GfElement(ydata[i])
+	}
+	return y, nil
+}
@@ -0,0 +1,67 @@
+package shamir
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	secret := []byte("protected by a passphrase")
+
+	s, err := NewShamirSecret(0x11d, 3, 2, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares := s.GetShares()
+
+	wrapped, err := shares[0].Wrap([]byte("correct horse"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(wrapped, WrappedSharePrefix+"-"+s.GetId()+"-11d-1-") {
+		t.Fatalf("unexpected wrapped share %s", wrapped)
+	}
+	if !IsWrapped(wrapped) || IsWrapped(shares[0].String()) {
+		t.Fatal("IsWrapped did not detect the wrapped share")
+	}
+
+	// the plain share in the same input does not need a passphrase
+	prompts := 0
+	input := wrapped + "\n" + shares[2].String()
+	recovered, err := UnwrapShares(input, func(label string) ([]byte, error) {
+		prompts++
+		return []byte("correct horse"), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prompts != 1 || len(recovered) != 2 {
+		t.Fatalf("prompted %d times for %d shares", prompts, len(recovered))
+	}
+
+	have, err := RecoverSecret(recovered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(have, secret) {
+		t.Fatalf("have %q, want %q", have, secret)
+	}
+
+	_, err = UnwrapShares(wrapped, func(label string) ([]byte, error) {
+		return []byte("wrong horse"), nil
+	})
+	if err != ErrWrongPassphrase {
+		t.Fatalf("have %v, want %v", err, ErrWrongPassphrase)
+	}
+
+	// the label is authenticated, so the share cannot be moved to another x coordinate
+	tampered := strings.Replace(wrapped, "-11d-1-", "-11d-2-", 1)
+	_, err = UnwrapShares(tampered, func(label string) ([]byte, error) {
+		return []byte("correct horse"), nil
+	})
+	if err != ErrWrongPassphrase {
+		t.Fatalf("have %v, want %v", err, ErrWrongPassphrase)
+	}
+}